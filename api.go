@@ -2,12 +2,17 @@ package tonrocket
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"regexp"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/shopspring/decimal"
@@ -17,7 +22,24 @@ type Currency string
 
 const TONCurrency Currency = "TONCOIN"
 
-const WebhookTypeInvoicePay = "invoicePay"
+// WebhookType is the kind of event a webhook reports. Rocket only sends webhooks for invoice
+// payments, multi-cheque activations have no webhook and have to be polled by comparing the
+// cheque's activation count between reads.
+type WebhookType string
+
+const (
+	UnknownWebhookType    WebhookType = "unknown"
+	WebhookTypeInvoicePay WebhookType = "invoicePay"
+)
+
+func (w WebhookType) IsKnown() bool {
+	switch w {
+	case WebhookTypeInvoicePay:
+		return true
+	}
+
+	return false
+}
 
 func (c Currency) String() string {
 	if c == TONCurrency {
@@ -27,6 +49,42 @@ func (c Currency) String() string {
 	return string(c)
 }
 
+type InvoiceStatus string
+
+// Rocket reports a single terminal unpaid status, expired. A deleted invoice is removed
+// rather than marked cancelled, so there is no status to tell the two apart.
+const (
+	InvoiceActive  InvoiceStatus = "active"
+	InvoicePaid    InvoiceStatus = "paid"
+	InvoiceExpired InvoiceStatus = "expired"
+)
+
+// Description returns a phrase for showing the status to people, or the raw value if the
+// status is unknown.
+func (s InvoiceStatus) Description() string {
+	switch s {
+	case InvoiceActive:
+		return "Awaiting payment"
+	case InvoicePaid:
+		return "Paid"
+	case InvoiceExpired:
+		return "Expired, not paid"
+	}
+
+	return string(s)
+}
+
+func (s *InvoiceStatus) UnmarshalJSON(data []byte) error {
+	var status string
+	if err := json.Unmarshal(data, &status); err != nil {
+		return err
+	}
+
+	*s = InvoiceStatus(strings.ToLower(strings.TrimSpace(status)))
+
+	return nil
+}
+
 type InvoiceID struct {
 	id string
 }
@@ -35,6 +93,14 @@ func (f *InvoiceID) String() string {
 	return f.id
 }
 
+func (f InvoiceID) MarshalJSON() ([]byte, error) {
+	if f.id == "" {
+		return []byte("null"), nil
+	}
+
+	return []byte(f.id), nil
+}
+
 func (f *InvoiceID) UnmarshalJSON(data []byte) error {
 	number := regexp.MustCompile("[0-9]+").FindString(string(data))
 
@@ -59,25 +125,51 @@ type CreateInvoiceRequest struct {
 	ExpiredIn     int      `json:"expiredIn"`
 }
 
+// Invoice is an invoice as returned by the API. It cannot be edited after creation.
 type Invoice struct {
-	ID               InvoiceID       `json:"id"`
-	Amount           decimal.Decimal `json:"amount"`
-	Description      string          `json:"description"`
-	HiddenMessage    string          `json:"hiddenMessage"`
-	Payload          string          `json:"payload"`
-	CallbackURL      string          `json:"callbackUrl"`
-	Currency         Currency        `json:"currency"`
-	Created          time.Time       `json:"created"`
-	Paid             time.Time       `json:"paid"`
-	Status           string          `json:"status"`
-	ExpiredIn        int             `json:"expiredIn"`
-	Link             string          `json:"link"`
-	TotalActivations int             `json:"totalActivations"`
-	ActivationsLeft  int             `json:"activationsLeft"`
-}
-
-type CreateTransferRequest *Transfer
+	ID            InvoiceID       `json:"id"`
+	Amount        decimal.Decimal `json:"amount"`
+	Description   string          `json:"description"`
+	HiddenMessage string          `json:"hiddenMessage"`
+	// Payload, like CallbackURL, is the closest proxy for whether the invoice was created
+	// through the API or in the Rocket bot, the API does not record it.
+	Payload     string        `json:"payload"`
+	CallbackURL string        `json:"callbackUrl"`
+	Currency    Currency      `json:"currency"`
+	Created     time.Time     `json:"created"`
+	Paid        time.Time     `json:"paid"`
+	Status      InvoiceStatus `json:"status"`
+	// ExpiredIn bounds both the invoice and its Link.
+	ExpiredIn int `json:"expiredIn"`
+	// Link has no validity window of its own, it works for as long as the invoice is active.
+	// Rocket does not track views or opens of it.
+	Link             string `json:"link"`
+	TotalActivations int    `json:"totalActivations"`
+	ActivationsLeft  int    `json:"activationsLeft"`
+	// Payment is only set in webhook data, for the activation that triggered the webhook.
+	Payment *InvoicePayment `json:"payment,omitempty"`
+}
+
+// InvoicePayment is a single activation of an invoice.
+type InvoicePayment struct {
+	UserID                int64           `json:"userId"`
+	PaymentNum            int             `json:"paymentNum"`
+	PaymentAmount         decimal.Decimal `json:"paymentAmount"`
+	PaymentAmountReceived decimal.Decimal `json:"paymentAmountReceived"`
+	Comment               string          `json:"comment"`
+	Paid                  time.Time       `json:"paid"`
+}
 
+type CreateTransferRequest struct {
+	TransferID  string          `json:"transferId"`
+	TgUserID    int64           `json:"tgUserId"`
+	Currency    Currency        `json:"currency"`
+	Amount      decimal.Decimal `json:"amount"`
+	Description string          `json:"description"`
+}
+
+// Transfer is a completed transfer. Rocket credits the user's wallet directly and does not
+// track whether the user noticed or opened it, so there is no acknowledgment to report.
 type Transfer struct {
 	ID          int64           `json:"id,omitempty"`
 	TransferID  string          `json:"transferId"`
@@ -88,47 +180,229 @@ type Transfer struct {
 }
 
 type InvoiceWebhookRequest struct {
-	Type      string    `json:"type"`
+	Type WebhookType `json:"type"`
+	// RawType is the type string as received, kept when Type is UnknownWebhookType.
+	RawType   string    `json:"-"`
 	Timestamp time.Time `json:"timestamp"`
 	Data      *Invoice  `json:"data"`
 }
 
+// AppInfo is the app's name, fee and balances. It carries no account state, an app that
+// Rocket has restricted is only noticed through the errors its requests return, and no
+// webhook URL, which is set in the Rocket bot and cannot be read through the API.
 type AppInfo struct {
-	Name        string           `json:"name"`
-	FeePercents decimal.Decimal  `json:"feePercents"`
-	Balances    []map[string]any `json:"balances"`
+	Name        string          `json:"name"`
+	FeePercents decimal.Decimal `json:"feePercents"`
+	Balances    []Balance       `json:"balances"`
+}
+
+type Balance struct {
+	Currency Currency        `json:"currency"`
+	Balance  decimal.Decimal `json:"balance"`
+}
+
+// BalanceOf returns the balance in currency. Rocket omits currencies the app never held, for
+// those it returns zero and false. A currency that is listed with a zero balance returns zero
+// and true.
+func (a *AppInfo) BalanceOf(currency Currency) (decimal.Decimal, bool) {
+	for _, b := range a.Balances {
+		if b.Currency == currency {
+			return b.Balance, true
+		}
+	}
+
+	return decimal.Zero, false
+}
+
+// BalancesFor returns one balance per currency in currencies, in that order, with zero for
+// currencies the app does not hold. Pass AvailableCurrencies to list every supported one.
+func (a *AppInfo) BalancesFor(currencies []*CurrencyInfo) []Balance {
+	balances := make([]Balance, len(currencies))
+	for i, c := range currencies {
+		amount, _ := a.BalanceOf(c.Currency)
+		balances[i] = Balance{Currency: c.Currency, Balance: amount}
+	}
+
+	return balances
 }
 
 const (
 	AuthHeader    = "Rocket-Pay-Key"
 	mainnetApiURL = "https://pay.ton-rocket.com"
-	testnetApiURL = "https://pay.ton-rocket.com"
+	testnetApiURL = "https://dev-pay.ton-rocket.com"
 )
 
+// DefaultMaxResponseBytes is the response body limit used unless WithMaxResponseBytes is set.
+const DefaultMaxResponseBytes = 10 << 20
+
+var ErrResponseTooLarge = errors.New("response body exceeds the size limit")
+
+// ErrNoData is returned when a response reports success but has no data for a method that
+// returns a result. Every method that returns a value expects data, only requests made
+// without a result value, such as deletes, accept an empty one.
+var ErrNoData = errors.New("response has no data")
+
 type tonrocket struct {
 	token       string
+	tokens      *tokenSource
 	httpClient  *http.Client
 	testingMode bool
+
+	balanceCheck       bool
+	withdrawalMinimums bool
+	partialData        bool
+
+	addressValidation bool
+
+	logger         Logger
+	schemaWarnings bool
+
+	payloadIdempotency int
+
+	defaultExpiry      time.Duration
+	defaultCurrency    Currency
+	defaultCallbackURL string
+
+	displayLocation *time.Location
+
+	successPredicate SuccessPredicate
+
+	endpoints Endpoints
+
+	middlewares []Middleware
+	roundTrip   RoundTripFunc
+
+	circuitBreaker *circuitBreaker
+	metrics        Metrics
+	responseHook   func(ResponseInfo)
+
+	lenientDecoding   bool
+	allowEmptyData    bool
+	precisionWarnings bool
+	hedgeDelay        time.Duration
+
+	retry             retryConfig
+	perAttemptTimeout time.Duration
+	maxResponseBytes  int64
+
+	diagnosticLog *diagnosticLog
+
+	pollJitter       pollJitter
+	feeMathPrecision int32
+
+	priceTable      PriceTable
+	currencyAliases map[Currency]Currency
+
+	idempotencyConflicts bool
+	clockCorrection      bool
+	deprecationHandler   func(endpoint string, sunset time.Time)
+
+	mu             sync.Mutex
+	rateLimit      RateLimitStatus
+	appInfoCall    *appInfoCall
+	currencies     []*CurrencyInfo
+	transferHashes map[string]string
+	transferOrder  []string
+	deprecated     map[string]bool
+	clockOffset    time.Duration
+
+	limiter limiter
+}
+
+type appInfoCall struct {
+	done chan struct{}
+	info *AppInfo
+	err  error
+}
+
+type page[T any] struct {
+	Total   int `json:"total"`
+	Limit   int `json:"limit"`
+	Offset  int `json:"offset"`
+	Results []T `json:"results"`
+}
+
+func pageParams(limit, offset int) url.Values {
+	return url.Values{
+		"limit":  {strconv.Itoa(limit)},
+		"offset": {strconv.Itoa(offset)},
+	}
 }
 
 type response struct {
 	Success bool             `json:"success"`
 	Message string           `json:"message"`
-	Errors  []*responseError `json:"errors"`
-	Data    any              `json:"data"`
+	Errors  []*ResponseError `json:"errors"`
+	Data    json.RawMessage  `json:"data"`
 }
 
-type responseError struct {
+type ResponseError struct {
 	Property string `json:"property"`
 	Error    string `json:"error"`
 }
 
+// SuccessPredicate decides from the raw response body whether the request succeeded, and
+// if not, the message and errors to report.
+type SuccessPredicate func(body []byte) (bool, string, []ResponseError)
+
+// APIError is returned when the response has success set to false. Rocket sends no error
+// codes, Message and each ResponseError.Error are prose that can change wording. The only
+// stable part is ResponseError.Property, the name of the request field that was rejected.
+// StatusCode is the HTTP status of the response.
+type APIError struct {
+	StatusCode int
+	Message    string
+	Errors     []*ResponseError
+}
+
+// Temporary reports whether the response status, 429 or 5xx, says the server could not handle
+// the request at the time, so sending it again may succeed.
+func (e *APIError) Temporary() bool {
+	return isRetriableStatus(e.StatusCode)
+}
+
+// HasProperty reports whether the API rejected the request field named property.
+func (e *APIError) HasProperty(property string) bool {
+	for _, err := range e.Errors {
+		if err.Property == property {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (e *APIError) Error() string {
+	var errs string
+	for i := range e.Errors {
+		errs = errs + fmt.Sprintf("%s: %s ", e.Errors[i].Property, e.Errors[i].Error)
+	}
+
+	return fmt.Sprintf("error received in response: %s | %s", e.Message, errs)
+}
+
+// ActivationAmount returns what the activation that triggered the webhook paid, which for a
+// multi-activation invoice can differ from the invoice amount. It is false if the webhook
+// carries no payment.
+func (r *InvoiceWebhookRequest) ActivationAmount() (decimal.Decimal, bool) {
+	if r.Data == nil || r.Data.Payment == nil {
+		return decimal.Zero, false
+	}
+
+	return r.Data.Payment.PaymentAmount, true
+}
+
 func ParseWebhookRequest(data []byte) (*InvoiceWebhookRequest, error) {
 	var webhookData InvoiceWebhookRequest
 	if err := json.Unmarshal(data, &webhookData); err != nil {
 		return nil, err
 	}
 
+	webhookData.RawType = string(webhookData.Type)
+	if !webhookData.Type.IsKnown() {
+		webhookData.Type = UnknownWebhookType
+	}
+
 	return &webhookData, nil
 }
 
@@ -140,46 +414,296 @@ func (c *tonrocket) getRequestUrl() string {
 	}
 }
 
-func NewTonrocket(token string) Tonrocket {
-	return &tonrocket{
+func NewTonrocket(token string, opts ...Option) Tonrocket {
+	t := &tonrocket{
 		token: token,
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
-		testingMode: false,
+		testingMode:       false,
+		addressValidation: true,
+		displayLocation:   time.UTC,
+		currencies:        loadEmbeddedCurrencies(),
+		endpoints:         DefaultEndpoints,
+		maxResponseBytes:  DefaultMaxResponseBytes,
+		pollJitter:        defaultPollJitter,
 	}
+
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	if t.circuitBreaker != nil && t.metrics != nil {
+		t.circuitBreaker.onChange = t.metrics.CircuitStateChanged
+	}
+
+	t.roundTrip = t.buildChain()
+
+	return t
+}
+
+// Logger is satisfied by *log.Logger.
+type Logger interface {
+	Printf(format string, v ...any)
+}
+
+type InvoiceReader interface {
+	GetInvoice(ctx context.Context, id string) (*Invoice, error)
+	ListInvoices(ctx context.Context, limit, offset int) ([]*Invoice, int, error)
+	InvoiceCount(ctx context.Context) (int, error)
+}
+
+type InvoiceWriter interface {
+	CreateInvoice(context.Context, CreateInvoiceRequest) (*Invoice, error)
+}
+
+type Transferer interface {
+	CreateTransfer(context.Context, CreateTransferRequest) (*Transfer, error)
+}
+
+type Withdrawer interface {
+	CreateWithdrawal(context.Context, CreateWithdrawalRequest) (*Withdrawal, error)
 }
 
+type AppReader interface {
+	AppInfo(ctx context.Context) (*AppInfo, error)
+	WithdrawalFees(ctx context.Context) ([]*WithdrawalFees, error)
+	AvailableCurrencies(ctx context.Context) ([]*CurrencyInfo, error)
+	GetMultiCheque(ctx context.Context, id int64) (*MultiCheque, error)
+}
+
+// Tonrocket is the full client. Components that must not move funds can be given one of
+// the narrower interfaces it embeds, e.g. InvoiceReader or AppReader.
 type Tonrocket interface {
-	CreateInvoice(CreateInvoiceRequest) (*Invoice, error)
-	CreateTransfer(CreateTransferRequest) (*Transfer, error)
-	AppInfo() (*AppInfo, error)
+	InvoiceReader
+	InvoiceWriter
+	Transferer
+	Withdrawer
+	AppReader
+
+	WaitForPayment(ctx context.Context, id string, interval time.Duration) (*Invoice, error)
+	CreateInvoiceAndWait(ctx context.Context, req CreateInvoiceRequest, interval time.Duration) (*Invoice, error)
+	CreateInvoices(ctx context.Context, reqs []CreateInvoiceRequest, concurrency int) ([]*Invoice, error)
+	CreateInvoiceForSKU(ctx context.Context, sku string, currency Currency) (*Invoice, error)
+	ServerTime(ctx context.Context) (time.Time, error)
+	ClockOffset(ctx context.Context) (time.Duration, error)
+	Now() time.Time
+	IsExpired(inv *Invoice) bool
+	TimeUntilExpiry(inv *Invoice) (time.Duration, bool)
+	RateLimitStatus() RateLimitStatus
+	SetRateLimit(rps float64, burst int)
+	SetMaxConcurrency(n int)
+	WatchBalances(ctx context.Context, interval time.Duration) (<-chan []Balance, error)
+	WaitForBalance(ctx context.Context, currency Currency, minimum decimal.Decimal, interval time.Duration) error
+	Config() ClientConfig
+	CircuitState() CircuitState
+	CurrencyLimits(currency Currency) (*CurrencyInfo, bool)
+	CurrenciesFor(op Operation) []*CurrencyInfo
+	CheckMinimum(op Operation, currency Currency, amount decimal.Decimal) error
+	NetAmount(currency Currency, gross, feePct decimal.Decimal) decimal.Decimal
+	GrossAmount(currency Currency, net, feePct decimal.Decimal) decimal.Decimal
+	DisplayTime(ts time.Time) time.Time
+	FormatTime(ts time.Time, layout string) string
 }
 
-func (t *tonrocket) AppInfo() (*AppInfo, error) {
-	var resp = &AppInfo{}
-	err := t.getRequest("/app/info", nil, resp)
+// AppInfo returns the app info. Concurrent calls share a single request, each gets its own
+// copy of the result. The shared request is not bound to any caller's context, so a caller
+// whose ctx ends stops waiting with ctx.Err() without failing the others. Its log lines carry
+// the request tag of the caller that started it.
+func (t *tonrocket) AppInfo(ctx context.Context) (*AppInfo, error) {
+	t.mu.Lock()
+	call := t.appInfoCall
+	if call == nil {
+		call = &appInfoCall{done: make(chan struct{}), info: &AppInfo{}}
+		t.appInfoCall = call
+		go t.fetchAppInfo(WithRequestTag(context.Background(), RequestTag(ctx)), call)
+	}
+	t.mu.Unlock()
 
-	return resp, err
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-call.done:
+	}
+
+	info := *call.info
+	info.Balances = append([]Balance(nil), call.info.Balances...)
+
+	err := call.err
+	if tag := RequestTag(ctx); err != nil && tag != "" {
+		err = fmt.Errorf("request %s: %w", tag, err)
+	}
+
+	return &info, err
 }
 
-func (t *tonrocket) CreateTransfer(req CreateTransferRequest) (*Transfer, error) {
+// fetchAppInfo runs the shared AppInfo request. Errors are not tagged here, every caller tags
+// them with its own request tag.
+func (t *tonrocket) fetchAppInfo(ctx context.Context, call *appInfoCall) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, t.getRequestUrl()+t.endpoints.AppInfo, nil)
+	if err == nil {
+		err = t.doRequest(req, call.info)
+	}
+	call.err = err
+
+	t.mu.Lock()
+	t.appInfoCall = nil
+	t.mu.Unlock()
+
+	close(call.done)
+}
+
+// CreateTransfer sends funds from the app balance to a Telegram user. Transfers are internal to
+// Rocket and settle synchronously: a returned Transfer is completed, a failed one comes back as
+// an error. There is no queued state and no endpoint to look a transfer up later.
+func (t *tonrocket) CreateTransfer(ctx context.Context, req CreateTransferRequest) (*Transfer, error) {
+	req.Currency = t.normalizeCurrency(req.Currency)
+
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	if isValidateOnly(ctx) {
+		if err := t.CheckMinimum(OperationTransfer, req.Currency, req.Amount); err != nil {
+			return nil, err
+		}
+		return nil, ErrValidatedLocally
+	}
+
+	hash, err := t.checkTransferConflict(req)
+	if err != nil {
+		return nil, err
+	}
+
 	var resp = &Transfer{}
 
-	err := t.postRequest("/app/transfer", req, resp)
+	err = t.postRequest(markIdempotent(ctx), t.endpoints.Transfer, req, resp)
+	if err == nil {
+		t.rememberTransfer(req.TransferID, hash)
+	}
 
 	return resp, err
 }
 
-func (t *tonrocket) CreateInvoice(req CreateInvoiceRequest) (*Invoice, error) {
+func (t *tonrocket) CreateInvoice(ctx context.Context, req CreateInvoiceRequest) (*Invoice, error) {
+	req = t.withInvoiceDefaults(req)
+
+	if isValidateOnly(ctx) {
+		if err := req.Validate(); err != nil {
+			return nil, err
+		}
+		if err := t.CheckMinimum(OperationInvoice, req.Currency, decimal.NewFromFloat(req.Amount)); err != nil {
+			return nil, err
+		}
+		return nil, ErrValidatedLocally
+	}
+
+	if key := IdempotencyKey(ctx); key != "" && t.payloadIdempotency > 0 {
+		hash := requestHash(req)
+
+		payload, err := embedIdempotencyKey(req.Payload, key, hash)
+		if err != nil {
+			return nil, err
+		}
+		req.Payload = payload
+
+		existing, err := t.findInvoiceByIdempotencyKey(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		if existing != nil {
+			return existing, t.checkInvoiceConflict(existing, hash)
+		}
+	}
+
 	var resp = &Invoice{}
 
-	err := t.postRequest("/tg-invoices", req, resp)
+	err := t.postRequest(ctx, t.endpoints.Invoices, req, resp)
 
 	return resp, err
 }
 
-func (t *tonrocket) postRequest(path string, body any, target any) error {
+// MaxInvoicePageSize is the largest limit the invoices endpoint accepts in one request.
+const MaxInvoicePageSize = 1000
+
+// InvoicePageLimit returns the limit ListInvoices sends in its first request for limit:
+// limit itself, clamped to MaxInvoicePageSize.
+func InvoicePageLimit(limit int) int {
+	if limit > MaxInvoicePageSize {
+		return MaxInvoicePageSize
+	}
+
+	return limit
+}
+
+// ListInvoices returns up to limit invoices starting at offset and the total count. Each
+// request asks for InvoicePageLimit of the invoices still missing, so a limit above
+// MaxInvoicePageSize is fetched as several requests. If the API returns fewer invoices than
+// asked while the total says more remain, for instance because it caps the page size below
+// MaxInvoicePageSize, the next request continues after them, so the result is only shorter
+// than limit when the list ends. If a request fails, the invoices fetched so far are returned
+// with the total of the last successful page and the error. The API only supports offset
+// pagination, it has no created-time filter to page by, so invoices created while iterating
+// shift later pages and can make an item appear twice.
+func (t *tonrocket) ListInvoices(ctx context.Context, limit, offset int) ([]*Invoice, int, error) {
+	if limit <= 0 {
+		return t.listInvoicesPage(ctx, limit, offset)
+	}
+
+	var (
+		invoices []*Invoice
+		total    int
+	)
+
+	for len(invoices) < limit {
+		n := InvoicePageLimit(limit - len(invoices))
+
+		results, pageTotal, err := t.listInvoicesPage(ctx, n, offset+len(invoices))
+		if err != nil {
+			return invoices, total, err
+		}
+
+		total = pageTotal
+		invoices = append(invoices, results...)
+
+		if len(results) == 0 || offset+len(invoices) >= total {
+			break
+		}
+	}
+
+	if len(invoices) > limit {
+		invoices = invoices[:limit]
+	}
+
+	return invoices, total, nil
+}
+
+func (t *tonrocket) listInvoicesPage(ctx context.Context, limit, offset int) ([]*Invoice, int, error) {
+	var resp = &page[*Invoice]{}
+
+	err := t.getRequest(ctx, t.endpoints.Invoices, pageParams(limit, offset), resp)
+
+	return resp.Results, resp.Total, err
+}
+
+// InvoiceCount returns the total number of invoices reported with a one item page. It is a
+// point-in-time value and can change right after it is read. There is no TransferCount: the
+// API has no endpoint listing transfers, so it reports no transfer total either.
+func (t *tonrocket) InvoiceCount(ctx context.Context) (int, error) {
+	_, total, err := t.ListInvoices(ctx, 1, 0)
+
+	return total, err
+}
+
+func (t *tonrocket) GetInvoice(ctx context.Context, id string) (*Invoice, error) {
+	var resp = &Invoice{}
+
+	err := t.getRequest(ctx, t.endpoints.Invoices+"/"+url.PathEscape(id), nil, resp)
+
+	return resp, err
+}
+
+func (t *tonrocket) postRequest(ctx context.Context, path string, body any, target any) error {
 	var buf bytes.Buffer
 	err := json.NewEncoder(&buf).Encode(body)
 
@@ -187,53 +711,215 @@ func (t *tonrocket) postRequest(path string, body any, target any) error {
 		return err
 	}
 
-	req, err := http.NewRequest(http.MethodPost, t.getRequestUrl()+path, &buf)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.getRequestUrl()+path, &buf)
 	if err != nil {
 		return err
 	}
 
 	req.Header.Set("Content-Type", "application/json")
 
-	resp := &response{
-		Data: target,
+	return t.makeRequest(req, target)
+}
+
+func (t *tonrocket) getRequest(ctx context.Context, path string, params url.Values, target any) error {
+	if len(params) > 0 {
+		path = path + "?" + params.Encode()
 	}
 
-	return t.makeRequest(req, resp)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, t.getRequestUrl()+path, nil)
+	if err != nil {
+		return err
+	}
+
+	return t.makeRequest(req, target)
 }
 
-func (t *tonrocket) getRequest(path string, params url.Values, target any) error {
-	req, err := http.NewRequest(http.MethodGet, t.getRequestUrl()+path, nil)
+func (t *tonrocket) makeRequest(req *http.Request, target any) error {
+	err := t.doRequest(req, target)
+	if tag := RequestTag(req.Context()); err != nil && tag != "" {
+		return fmt.Errorf("request %s: %w", tag, err)
+	}
+
+	return err
+}
+
+func (t *tonrocket) doRequest(req *http.Request, target any) error {
+	resp, err := t.roundTrip(req)
+
+	if err != nil {
+		err = fmt.Errorf("error while performing a request: %w", err)
+		t.fireResponseHook(req, nil, nil, nil, err)
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	body, err := t.readBody(resp.Body)
 	if err != nil {
+		err = fmt.Errorf("error while reading a response: %w", err)
+		t.fireResponseHook(req, resp, body, nil, err)
 		return err
 	}
 
-	resp := &response{
-		Data: target,
+	if until, ok := maintenanceUntil(resp); ok {
+		err = &MaintenanceError{Until: until}
+		t.fireResponseHook(req, resp, body, nil, err)
+		return err
+	}
+
+	var warnings []string
+	err = t.decodeResponse(req, body, target, &warnings)
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		apiErr.StatusCode = resp.StatusCode
 	}
+	t.fireResponseHook(req, resp, body, warnings, err)
 
-	return t.makeRequest(req, resp)
+	return err
 }
 
-func (t *tonrocket) makeRequest(req *http.Request, target *response) error {
-	req.Header.Set(AuthHeader, t.token)
-	resp, err := t.httpClient.Do(req)
+func (t *tonrocket) readBody(body io.Reader) ([]byte, error) {
+	if t.maxResponseBytes <= 0 {
+		return io.ReadAll(body)
+	}
 
+	data, err := io.ReadAll(io.LimitReader(body, t.maxResponseBytes+1))
 	if err != nil {
-		return fmt.Errorf("error while performing a request: %w", err)
+		return data, err
 	}
 
-	err = json.NewDecoder(resp.Body).Decode(target)
-	if err != nil {
+	if int64(len(data)) > t.maxResponseBytes {
+		return nil, fmt.Errorf("%w: limit is %d bytes", ErrResponseTooLarge, t.maxResponseBytes)
+	}
+
+	return data, nil
+}
+
+func (t *tonrocket) decodeResponse(req *http.Request, body []byte, target any, warnings *[]string) error {
+	var envelope response
+	err := json.Unmarshal(body, &envelope)
+	if err != nil && t.successPredicate == nil {
 		return err
 	}
 
-	if !target.Success {
-		var errs string
-		for i := range target.Errors {
-			errs = errs + fmt.Sprintf("%s: %s ", target.Errors[i].Property, target.Errors[i].Error)
+	if t.successPredicate != nil {
+		var errs []ResponseError
+		envelope.Success, envelope.Message, errs = t.successPredicate(body)
+		envelope.Errors = make([]*ResponseError, len(errs))
+		for i := range errs {
+			envelope.Errors[i] = &errs[i]
 		}
-		return fmt.Errorf("error received in response: %s | %s", target.Message, errs)
 	}
 
-	return nil
+	if !envelope.Success {
+		apiErr := &APIError{
+			Message: envelope.Message,
+			Errors:  envelope.Errors,
+		}
+
+		if t.partialData && hasData(envelope.Data) {
+			_ = json.Unmarshal(envelope.Data, target)
+		}
+
+		return apiErr
+	}
+
+	if !hasData(envelope.Data) {
+		if target == nil || t.allowEmptyData {
+			return nil
+		}
+		return ErrNoData
+	}
+
+	if t.schemaWarnings && t.logger != nil {
+		t.checkSchema(req, envelope.Data, target)
+	}
+
+	err = json.Unmarshal(envelope.Data, target)
+	if err != nil && t.lenientDecoding {
+		err = lenientUnmarshal(envelope.Data, target, warnings)
+	}
+
+	if err == nil && t.precisionWarnings {
+		checkPrecision(target, warnings)
+	}
+
+	return err
+}
+
+func hasData(data json.RawMessage) bool {
+	return len(data) > 0 && string(data) != "null"
+}
+
+// PaidAmount returns the amount collected so far. Rocket does not report a paid total, it is
+// derived as Amount times the number of used activations, which assumes every activation paid
+// Amount.
+func (i *Invoice) PaidAmount() decimal.Decimal {
+	used := i.TotalActivations - i.ActivationsLeft
+	if used <= 0 {
+		return decimal.Zero
+	}
+
+	return i.Amount.Mul(decimal.NewFromInt(int64(used)))
+}
+
+// Fee returns Rocket's fee on PaidAmount at feePct percent, as reported in
+// AppInfo.FeePercents, so that Fee plus NetReceived is PaidAmount. The invoice response
+// carries no fee of its own, so an app with a negotiated per-invoice rate has to pass that
+// rate itself.
+func (i *Invoice) Fee(feePct decimal.Decimal) decimal.Decimal {
+	return i.PaidAmount().Sub(i.NetReceived(feePct))
+}
+
+// NetReceived returns the amount credited to the app balance for PaidAmount, computed like
+// NetAmount with the default precision: rounded down to the currency decimals.
+func (i *Invoice) NetReceived(feePct decimal.Decimal) decimal.Decimal {
+	return netAmount(i.Currency, i.PaidAmount(), feePct, 0)
+}
+
+// RemainingAmount returns the amount still to be collected, never below zero.
+func (i *Invoice) RemainingAmount() decimal.Decimal {
+	if i.TotalActivations <= 0 {
+		return decimal.Zero
+	}
+
+	target := i.Amount.Mul(decimal.NewFromInt(int64(i.TotalActivations)))
+	remaining := target.Sub(i.PaidAmount())
+	if remaining.IsNegative() {
+		return decimal.Zero
+	}
+
+	return remaining
+}
+
+// IsMultiActivation reports whether the invoice can be paid more than once.
+func (i *Invoice) IsMultiActivation() bool {
+	return i.TotalActivations > 1
+}
+
+// AcceptsMorePayments reports whether the invoice is active and has activations left.
+// An ActivationsLeft outside 0..TotalActivations is treated as none left.
+func (i *Invoice) AcceptsMorePayments() bool {
+	if i.Status != InvoiceActive {
+		return false
+	}
+
+	return i.ActivationsLeft > 0 && i.ActivationsLeft <= i.TotalActivations
+}
+
+// Before reports whether i was created before o. Created has the precision the API sends,
+// which can be whole seconds, so ties are broken by the id: Rocket assigns ids in increasing
+// order, which makes the result deterministic for invoices created in the same second.
+func (i *Invoice) Before(o *Invoice) bool {
+	if !i.Created.Equal(o.Created) {
+		return i.Created.Before(o.Created)
+	}
+
+	a, b := i.ID.String(), o.ID.String()
+	if len(a) != len(b) {
+		return len(a) < len(b)
+	}
+
+	return a < b
 }
@@ -0,0 +1,170 @@
+package tonrocket
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestMaxResponseBytes(t *testing.T) {
+	tests := []struct {
+		name    string
+		limit   int64
+		nameLen int
+		wantErr bool
+	}{
+		{"under the limit", 200, 10, false},
+		{"over the limit", 200, 1000, true},
+		{"no limit", 0, 1 << 16, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				writeData(w, AppInfo{Name: strings.Repeat("x", tt.nameLen)})
+			}, WithMaxResponseBytes(tt.limit))
+
+			info, err := client.AppInfo(context.Background())
+			if tt.wantErr {
+				if !errors.Is(err, ErrResponseTooLarge) {
+					t.Fatalf("err = %v, want ErrResponseTooLarge", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("AppInfo: %v", err)
+			}
+			if len(info.Name) != tt.nameLen {
+				t.Fatalf("name has %d characters, want %d", len(info.Name), tt.nameLen)
+			}
+		})
+	}
+}
+
+func TestListInvoicesPageSize(t *testing.T) {
+	const total, serverCap = 5, 2
+
+	tests := []struct {
+		name         string
+		limit        int
+		wantCount    int
+		wantRequests int
+	}{
+		{"within the server cap", 2, 2, 1},
+		{"above the server cap", 4, 4, 2},
+		{"above the total", 10, 5, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests int
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+				limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+				if limit > serverCap {
+					limit = serverCap
+				}
+
+				results := []map[string]any{}
+				for i := offset; i < offset+limit && i < total; i++ {
+					results = append(results, map[string]any{"id": i + 1})
+				}
+				writeData(w, map[string]any{"total": total, "limit": limit, "offset": offset, "results": results})
+			})
+
+			invoices, gotTotal, err := client.ListInvoices(context.Background(), tt.limit, 0)
+			if err != nil {
+				t.Fatalf("ListInvoices: %v", err)
+			}
+			if len(invoices) != tt.wantCount || gotTotal != total {
+				t.Fatalf("got %d invoices of %d, want %d of %d", len(invoices), gotTotal, tt.wantCount, total)
+			}
+			if requests != tt.wantRequests {
+				t.Fatalf("requests = %d, want %d", requests, tt.wantRequests)
+			}
+		})
+	}
+}
+
+func TestListInvoicesPageError(t *testing.T) {
+	var requests int
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests > 1 {
+			writeError(w, http.StatusInternalServerError, "failed")
+			return
+		}
+		writeData(w, map[string]any{"total": 5000, "results": make([]map[string]any, MaxInvoicePageSize)})
+	})
+
+	invoices, total, err := client.ListInvoices(context.Background(), 2000, 0)
+	if err == nil {
+		t.Fatal("no error for the failed page")
+	}
+	if len(invoices) != MaxInvoicePageSize || total != 5000 {
+		t.Fatalf("got %d invoices of %d, want the first page and its total", len(invoices), total)
+	}
+}
+
+func TestInvoicePageLimit(t *testing.T) {
+	for limit, want := range map[int]int{1: 1, MaxInvoicePageSize: MaxInvoicePageSize, 2500: MaxInvoicePageSize} {
+		if got := InvoicePageLimit(limit); got != want {
+			t.Errorf("InvoicePageLimit(%d) = %d, want %d", limit, got, want)
+		}
+	}
+}
+
+func TestAppInfoSharedRequest(t *testing.T) {
+	var requests int
+	started := make(chan struct{})
+	release := make(chan struct{})
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		close(started)
+		<-release
+		writeData(w, AppInfo{Name: "app", Balances: []Balance{{Currency: TONCurrency, Balance: decimal.NewFromInt(5)}}})
+	})
+
+	type result struct {
+		info *AppInfo
+		err  error
+	}
+	call := func(ctx context.Context) <-chan result {
+		ch := make(chan result, 1)
+		go func() {
+			info, err := client.AppInfo(ctx)
+			ch <- result{info, err}
+		}()
+		return ch
+	}
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	leader := call(cancelled)
+	<-started
+	first, second := call(context.Background()), call(context.Background())
+
+	cancel()
+	if res := <-leader; !errors.Is(res.err, context.Canceled) {
+		t.Fatalf("cancelled caller got %v, want context.Canceled", res.err)
+	}
+
+	close(release)
+	a, b := <-first, <-second
+	if a.err != nil || b.err != nil {
+		t.Fatalf("waiting callers failed: %v, %v", a.err, b.err)
+	}
+	if requests != 1 {
+		t.Fatalf("requests = %d, want 1", requests)
+	}
+
+	a.info.Balances[0].Balance = decimal.Zero
+	if !b.info.Balances[0].Balance.Equal(decimal.NewFromInt(5)) {
+		t.Fatal("callers share the Balances slice")
+	}
+}
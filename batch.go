@@ -0,0 +1,48 @@
+package tonrocket
+
+import (
+	"context"
+	"strconv"
+	"sync"
+)
+
+// CreateInvoices creates the invoices with at most concurrency requests in flight. Results are
+// in the order of reqs. If any item failed the error is a *MultiError whose Errors are in the
+// order of reqs too, an item failed if its error is non-nil.
+//
+// When ctx carries an idempotency key, every item is sent with that key suffixed by the item
+// index, so retrying the batch with the same key does not recreate invoices that were created
+// by the first attempt. Without a key in ctx no item carries one: identical requests in a batch
+// are distinct invoices. The keys only take effect with WithPayloadIdempotency.
+func (t *tonrocket) CreateInvoices(ctx context.Context, reqs []CreateInvoiceRequest, concurrency int) ([]*Invoice, error) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	var (
+		invoices = make([]*Invoice, len(reqs))
+		errs     = make([]error, len(reqs))
+		wg       sync.WaitGroup
+		sem      = make(chan struct{}, concurrency)
+	)
+
+	for i := range reqs {
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			itemCtx := ctx
+			if key := IdempotencyKey(ctx); key != "" {
+				itemCtx = WithIdempotencyKey(ctx, key+"/"+strconv.Itoa(i))
+			}
+			invoices[i], errs[i] = t.CreateInvoice(itemCtx, reqs[i])
+		}(i)
+	}
+
+	wg.Wait()
+
+	return invoices, NewMultiError(errs)
+}
@@ -0,0 +1,140 @@
+package tonrocket
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+type CircuitState int
+
+const (
+	CircuitClosed CircuitState = iota
+	CircuitOpen
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+
+	return "unknown"
+}
+
+// circuitBreaker opens after threshold consecutive failures, a transport error or a 5xx
+// response, and rejects requests with ErrCircuitOpen until cooldown has passed. Then a
+// single probe request is let through: success closes the circuit, failure opens it again.
+type circuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+	onChange  func(CircuitState)
+
+	mu       sync.Mutex
+	state    CircuitState
+	failures int
+	openedAt time.Time
+}
+
+func (cb *circuitBreaker) State() CircuitState {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	return cb.state
+}
+
+func (cb *circuitBreaker) allow() bool {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	switch cb.state {
+	case CircuitOpen:
+		if time.Since(cb.openedAt) < cb.cooldown {
+			return false
+		}
+		cb.setState(CircuitHalfOpen)
+		return true
+	case CircuitHalfOpen:
+		return false
+	}
+
+	return true
+}
+
+func (cb *circuitBreaker) record(failed bool) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if !failed {
+		cb.setState(CircuitClosed)
+		cb.failures = 0
+		return
+	}
+
+	cb.failures++
+	if cb.state == CircuitHalfOpen || cb.failures >= cb.threshold {
+		cb.setState(CircuitOpen)
+		cb.openedAt = time.Now()
+	}
+}
+
+// setState must be called with mu held.
+func (cb *circuitBreaker) setState(state CircuitState) {
+	if cb.state == state {
+		return
+	}
+
+	cb.state = state
+	if cb.onChange != nil {
+		cb.onChange(state)
+	}
+}
+
+func (cb *circuitBreaker) middleware(next RoundTripFunc) RoundTripFunc {
+	return func(req *http.Request) (*http.Response, error) {
+		if err := req.Context().Err(); err != nil {
+			return nil, err
+		}
+
+		if !cb.allow() {
+			return nil, ErrCircuitOpen
+		}
+
+		resp, err := next(req)
+		if err != nil && req.Context().Err() != nil {
+			// The caller gave up, that says nothing about the server. Give the probe slot back.
+			cb.release()
+			return resp, err
+		}
+
+		cb.record(err != nil || resp.StatusCode >= http.StatusInternalServerError)
+
+		return resp, err
+	}
+}
+
+func (cb *circuitBreaker) release() {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if cb.state == CircuitHalfOpen {
+		cb.setState(CircuitOpen)
+	}
+}
+
+// CircuitState returns the circuit breaker state, always CircuitClosed if it is not enabled.
+func (t *tonrocket) CircuitState() CircuitState {
+	if t.circuitBreaker == nil {
+		return CircuitClosed
+	}
+
+	return t.circuitBreaker.State()
+}
@@ -0,0 +1,76 @@
+package tonrocket
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreakerTransitions(t *testing.T) {
+	client := NewTonrocket("token", WithCircuitBreaker(2, 20*time.Millisecond)).(*tonrocket)
+
+	cfg := client.Config()
+	if cfg.BreakerThreshold != 2 || cfg.BreakerCooldown != 20*time.Millisecond {
+		t.Fatalf("config = %+v", cfg)
+	}
+
+	var states []CircuitState
+	client.circuitBreaker.onChange = func(s CircuitState) { states = append(states, s) }
+
+	status := http.StatusInternalServerError
+	calls := 0
+	rt := client.circuitBreaker.middleware(func(req *http.Request) (*http.Response, error) {
+		calls++
+		return &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader(""))}, nil
+	})
+
+	send := func() error {
+		req, _ := http.NewRequest(http.MethodGet, "https://example.com", nil)
+		_, err := rt(req)
+		return err
+	}
+
+	steps := []struct {
+		name      string
+		status    int
+		wait      time.Duration
+		wantErr   error
+		wantState CircuitState
+		wantCalls int
+	}{
+		{"first failure", http.StatusInternalServerError, 0, nil, CircuitClosed, 1},
+		{"threshold reached", http.StatusInternalServerError, 0, nil, CircuitOpen, 2},
+		{"fails fast while open", http.StatusOK, 0, ErrCircuitOpen, CircuitOpen, 2},
+		{"failed probe reopens", http.StatusBadGateway, 30 * time.Millisecond, nil, CircuitOpen, 3},
+		{"successful probe closes", http.StatusOK, 30 * time.Millisecond, nil, CircuitClosed, 4},
+		{"closed passes through", http.StatusOK, 0, nil, CircuitClosed, 5},
+	}
+
+	for _, step := range steps {
+		time.Sleep(step.wait)
+		status = step.status
+
+		if err := send(); !errors.Is(err, step.wantErr) {
+			t.Fatalf("%s: err = %v, want %v", step.name, err, step.wantErr)
+		}
+		if got := client.CircuitState(); got != step.wantState {
+			t.Fatalf("%s: state = %v, want %v", step.name, got, step.wantState)
+		}
+		if calls != step.wantCalls {
+			t.Fatalf("%s: calls = %d, want %d", step.name, calls, step.wantCalls)
+		}
+	}
+
+	want := []CircuitState{CircuitOpen, CircuitHalfOpen, CircuitOpen, CircuitHalfOpen, CircuitClosed}
+	if len(states) != len(want) {
+		t.Fatalf("transitions = %v, want %v", states, want)
+	}
+	for i := range want {
+		if states[i] != want[i] {
+			t.Fatalf("transitions = %v, want %v", states, want)
+		}
+	}
+}
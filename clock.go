@@ -0,0 +1,61 @@
+package tonrocket
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// ServerTime returns the server clock as reported by the Date header of a /version request.
+// The header has one second resolution.
+func (t *tonrocket) ServerTime(ctx context.Context) (time.Time, error) {
+	serverTime, _, err := t.serverTime(ctx)
+
+	return serverTime, err
+}
+
+// ClockOffset returns how far the server clock is ahead of the local one. The local time
+// is taken at the middle of the round trip, the result is accurate to about a second.
+func (t *tonrocket) ClockOffset(ctx context.Context) (time.Duration, error) {
+	serverTime, localTime, err := t.serverTime(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	offset := serverTime.Sub(localTime)
+	t.setClockOffset(offset)
+
+	return offset, nil
+}
+
+func (t *tonrocket) serverTime(ctx context.Context) (time.Time, time.Time, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, t.getRequestUrl()+t.endpoints.Version, nil)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	start := time.Now()
+	resp, err := t.roundTrip(req)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("error while performing a request: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	localTime := start.Add(time.Since(start) / 2)
+
+	date := resp.Header.Get("Date")
+	if date == "" {
+		return time.Time{}, time.Time{}, errors.New("response has no Date header")
+	}
+
+	serverTime, err := http.ParseTime(date)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("unable to parse Date header: %w", err)
+	}
+
+	return serverTime, localTime, nil
+}
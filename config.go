@@ -0,0 +1,66 @@
+package tonrocket
+
+import "time"
+
+// ClientConfig is a snapshot of the settings a client was created with. The token is
+// redacted so the value is safe to log.
+type ClientConfig struct {
+	Environment        string
+	BaseURL            string
+	Token              string
+	Timeout            time.Duration
+	BalanceCheck       bool
+	PartialData        bool
+	AddressValidation  bool
+	DefaultExpiry      time.Duration
+	DefaultCurrency    Currency
+	DefaultCallbackURL string
+	DisplayLocation    string
+	Endpoints          Endpoints
+	RetryMaxAttempts   int
+	RetryBackoff       time.Duration
+	PerAttemptTimeout  time.Duration
+	RateLimit          float64
+	RateLimitBurst     int
+	MaxConcurrency     int
+	BreakerThreshold   int
+	BreakerCooldown    time.Duration
+	HedgeDelay         time.Duration
+}
+
+func (t *tonrocket) Config() ClientConfig {
+	cfg := ClientConfig{
+		Environment:        "mainnet",
+		BaseURL:            t.getRequestUrl(),
+		Timeout:            t.httpClient.Timeout,
+		BalanceCheck:       t.balanceCheck,
+		PartialData:        t.partialData,
+		AddressValidation:  t.addressValidation,
+		DefaultExpiry:      t.defaultExpiry,
+		DefaultCurrency:    t.defaultCurrency,
+		DefaultCallbackURL: t.defaultCallbackURL,
+		DisplayLocation:    t.displayLocation.String(),
+		Endpoints:          t.endpoints,
+		RetryMaxAttempts:   t.retry.maxAttempts,
+		RetryBackoff:       t.retry.backoff,
+		PerAttemptTimeout:  t.perAttemptTimeout,
+		HedgeDelay:         t.hedgeDelay,
+	}
+
+	cfg.RateLimit, cfg.RateLimitBurst, cfg.MaxConcurrency = t.limiter.limits()
+
+	if t.circuitBreaker != nil {
+		cfg.BreakerThreshold = t.circuitBreaker.threshold
+		cfg.BreakerCooldown = t.circuitBreaker.cooldown
+	}
+
+	if t.testingMode {
+		cfg.Environment = "testnet"
+	}
+
+	if t.token != "" || t.tokens != nil {
+		cfg.Token = "[redacted]"
+	}
+
+	return cfg
+}
@@ -0,0 +1,30 @@
+package tonrocket
+
+import "context"
+
+type requestTagKey struct{}
+
+// WithRequestTag returns a context carrying tag. Requests made with the context include
+// the tag in the errors they return, in the ResponseInfo passed to the response hook and in
+// the lines written to the logger, so concurrent calls can be told apart in logs. A Metrics
+// receives it only if it implements TaggedMetrics: tags such as order ids are unbounded, so
+// they are kept out of the regular metric methods and the Prometheus collector's labels.
+func WithRequestTag(ctx context.Context, tag string) context.Context {
+	return context.WithValue(ctx, requestTagKey{}, tag)
+}
+
+// RequestTag returns the tag set with WithRequestTag, or an empty string.
+func RequestTag(ctx context.Context) string {
+	tag, _ := ctx.Value(requestTagKey{}).(string)
+
+	return tag
+}
+
+// logPrefix starts a logger line, with the request tag in ctx if there is one.
+func logPrefix(ctx context.Context) string {
+	if tag := RequestTag(ctx); tag != "" {
+		return "tonrocket: request " + tag + ": "
+	}
+
+	return "tonrocket: "
+}
@@ -0,0 +1,29 @@
+package tonrocket
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestRequestTagInLogs(t *testing.T) {
+	logger := &recordingLogger{}
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Deprecation", "true")
+		writeData(w, map[string]any{"name": "app", "feePercents": "1", "balances": nil, "extra": 1})
+	}, WithSchemaWarnings(true), WithLogger(logger))
+
+	ctx := WithRequestTag(context.Background(), "order-42")
+	if _, err := client.AppInfo(ctx); err != nil {
+		t.Fatalf("AppInfo: %v", err)
+	}
+
+	for _, want := range []string{
+		`tonrocket: request order-42: /app/info: unexpected field "extra"`,
+		"tonrocket: request order-42: endpoint ",
+	} {
+		if !logger.contains(want) {
+			t.Errorf("no line containing %q in %q", want, logger.lines)
+		}
+	}
+}
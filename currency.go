@@ -0,0 +1,142 @@
+package tonrocket
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+type Operation string
+
+const (
+	OperationInvoice  Operation = "invoice"
+	OperationTransfer Operation = "transfer"
+	OperationCheque   Operation = "cheque"
+	OperationWithdraw Operation = "withdraw"
+)
+
+type CurrencyInfo struct {
+	Currency    Currency        `json:"currency"`
+	Name        string          `json:"name"`
+	MinTransfer decimal.Decimal `json:"minTransfer"`
+	MinCheque   decimal.Decimal `json:"minCheque"`
+	MinInvoice  decimal.Decimal `json:"minInvoice"`
+	MinWithdraw decimal.Decimal `json:"minWithdraw"`
+}
+
+func (c *CurrencyInfo) MinimumFor(op Operation) decimal.Decimal {
+	switch op {
+	case OperationInvoice:
+		return c.MinInvoice
+	case OperationTransfer:
+		return c.MinTransfer
+	case OperationCheque:
+		return c.MinCheque
+	case OperationWithdraw:
+		return c.MinWithdraw
+	}
+
+	return decimal.Zero
+}
+
+// Supports reports whether the currency can be used for op. Rocket has no per-operation
+// flags, a currency counts as supported when it reports a positive minimum for op, and a
+// missing minimum counts as unsupported.
+func (c *CurrencyInfo) Supports(op Operation) bool {
+	return c.MinimumFor(op).IsPositive()
+}
+
+// embeddedCurrencies is a snapshot of /currencies/available shipped with the package. It
+// may be stale, AvailableCurrencies is authoritative.
+//
+//go:embed data/currencies.json
+var embeddedCurrencies []byte
+
+func loadEmbeddedCurrencies() []*CurrencyInfo {
+	var currencies []*CurrencyInfo
+	if err := json.Unmarshal(embeddedCurrencies, &currencies); err != nil {
+		panic(fmt.Sprintf("tonrocket: invalid embedded currencies: %v", err))
+	}
+
+	return currencies
+}
+
+// AvailableCurrencies fetches the currencies Rocket supports with their limits. The result
+// replaces the embedded snapshot used by CurrencyLimits and CheckMinimum.
+func (t *tonrocket) AvailableCurrencies(ctx context.Context) ([]*CurrencyInfo, error) {
+	var resp = &page[*CurrencyInfo]{}
+
+	err := t.getRequest(ctx, t.endpoints.Currencies, nil, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	t.mu.Lock()
+	t.currencies = resp.Results
+	t.mu.Unlock()
+
+	return resp.Results, nil
+}
+
+// CurrencyLimits returns the limits of currency from the last AvailableCurrencies call, or
+// from the embedded snapshot if it has not been called yet.
+func (t *tonrocket) CurrencyLimits(currency Currency) (*CurrencyInfo, bool) {
+	t.mu.Lock()
+	currencies := t.currencies
+	t.mu.Unlock()
+
+	return findCurrency(currencies, t.normalizeCurrency(currency))
+}
+
+// CurrenciesFor returns the currencies that support op, see CurrencyInfo.Supports, from the
+// same data as CurrencyLimits.
+func (t *tonrocket) CurrenciesFor(op Operation) []*CurrencyInfo {
+	t.mu.Lock()
+	currencies := t.currencies
+	t.mu.Unlock()
+
+	return currenciesFor(currencies, op)
+}
+
+func currenciesFor(currencies []*CurrencyInfo, op Operation) []*CurrencyInfo {
+	var supported []*CurrencyInfo
+	for _, c := range currencies {
+		if c.Supports(op) {
+			supported = append(supported, c)
+		}
+	}
+
+	return supported
+}
+
+// CheckMinimum returns an error if amount is below the minimum for op in currency. Unknown
+// currencies are not checked.
+func (t *tonrocket) CheckMinimum(op Operation, currency Currency, amount decimal.Decimal) error {
+	info, ok := t.CurrencyLimits(currency)
+	if !ok {
+		return nil
+	}
+
+	return checkMinimum(info, op, amount)
+}
+
+func checkMinimum(info *CurrencyInfo, op Operation, amount decimal.Decimal) error {
+	if min := info.MinimumFor(op); amount.LessThan(min) {
+		return fmt.Errorf("%s amount %s is below the minimum of %s %s", op, amount, min, info.Currency)
+	}
+
+	return nil
+}
+
+func findCurrency(currencies []*CurrencyInfo, currency Currency) (*CurrencyInfo, bool) {
+	for _, c := range currencies {
+		if c.Currency == currency {
+			return c, true
+		}
+	}
+
+	return nil, false
+}
@@ -0,0 +1,22 @@
+package tonrocket
+
+import "strings"
+
+// DefaultCurrencyAliases maps the common alternative spellings to the wire values.
+var DefaultCurrencyAliases = map[Currency]Currency{
+	"TON": TONCurrency,
+}
+
+// normalizeCurrency maps an alias set with WithCurrencyAliases to its canonical value. The
+// lookup ignores case and surrounding space, values that are not aliases pass through.
+func (t *tonrocket) normalizeCurrency(currency Currency) Currency {
+	if len(t.currencyAliases) == 0 {
+		return currency
+	}
+
+	if canonical, ok := t.currencyAliases[Currency(strings.ToUpper(strings.TrimSpace(string(currency))))]; ok {
+		return canonical
+	}
+
+	return currency
+}
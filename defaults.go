@@ -0,0 +1,23 @@
+package tonrocket
+
+import "time"
+
+// withInvoiceDefaults fills unset fields of req from the client defaults. req is a copy, the
+// caller's value is never modified, and the defaults are read-only after construction, so
+// concurrent calls sharing a request are safe.
+func (t *tonrocket) withInvoiceDefaults(req CreateInvoiceRequest) CreateInvoiceRequest {
+	if req.ExpiredIn == 0 && t.defaultExpiry > 0 {
+		req.ExpiredIn = int(t.defaultExpiry / time.Second)
+	}
+
+	if req.Currency == "" {
+		req.Currency = t.defaultCurrency
+	}
+	req.Currency = t.normalizeCurrency(req.Currency)
+
+	if req.CallbackURL == "" {
+		req.CallbackURL = t.defaultCallbackURL
+	}
+
+	return req
+}
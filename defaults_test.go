@@ -0,0 +1,85 @@
+package tonrocket
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestInvoiceDefaultsDoNotMutateRequest(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		bodies []CreateInvoiceRequest
+	)
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var body CreateInvoiceRequest
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		mu.Lock()
+		bodies = append(bodies, body)
+		mu.Unlock()
+		writeData(w, map[string]any{"id": 1})
+	},
+		WithDefaultExpiry(time.Hour),
+		WithDefaultCurrency(TONCurrency),
+		WithDefaultCallbackURL("https://example.com/hook"),
+	)
+
+	req := CreateInvoiceRequest{Amount: 1, NumPayments: 1}
+	original := req
+
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.CreateInvoice(context.Background(), req); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if req != original {
+		t.Fatalf("request changed to %+v", req)
+	}
+	for _, body := range bodies {
+		if body.ExpiredIn != 3600 || body.Currency != TONCurrency || body.CallbackURL != "https://example.com/hook" {
+			t.Fatalf("body = %+v, want the defaults applied", body)
+		}
+	}
+	if len(bodies) != 2 {
+		t.Fatalf("got %d requests, want 2", len(bodies))
+	}
+}
+
+func TestDefaultExpiry(t *testing.T) {
+	tests := []struct {
+		name      string
+		expiredIn int
+		want      int
+	}{
+		{"unset takes the default", 0, 3600},
+		{"explicit value wins", 600, 600},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body CreateInvoiceRequest
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				_ = json.NewDecoder(r.Body).Decode(&body)
+				writeData(w, map[string]any{"id": 1})
+			}, WithDefaultExpiry(time.Hour))
+
+			req := CreateInvoiceRequest{Amount: 1, NumPayments: 1, Currency: TONCurrency, ExpiredIn: tt.expiredIn}
+			if _, err := client.CreateInvoice(context.Background(), req); err != nil {
+				t.Fatalf("CreateInvoice: %v", err)
+			}
+			if body.ExpiredIn != tt.want {
+				t.Fatalf("expiredIn = %d, want %d", body.ExpiredIn, tt.want)
+			}
+		})
+	}
+}
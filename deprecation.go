@@ -0,0 +1,65 @@
+package tonrocket
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	deprecationHeader = "Deprecation"
+	sunsetHeader      = "Sunset"
+)
+
+// checkDeprecation reports an endpoint the first time a response for it carries a
+// Deprecation or Sunset header. It is logged and passed to the deprecation handler once per
+// endpoint for the lifetime of the client.
+func (t *tonrocket) checkDeprecation(req *http.Request, header http.Header) {
+	deprecation := header.Get(deprecationHeader)
+	sunsetValue := header.Get(sunsetHeader)
+	if deprecation == "" && sunsetValue == "" {
+		return
+	}
+
+	endpoint := endpointLabel(req.URL.Path)
+
+	t.mu.Lock()
+	if t.deprecated[endpoint] {
+		t.mu.Unlock()
+		return
+	}
+	if t.deprecated == nil {
+		t.deprecated = make(map[string]bool)
+	}
+	t.deprecated[endpoint] = true
+	t.mu.Unlock()
+
+	sunset, _ := http.ParseTime(sunsetValue)
+
+	if t.logger != nil {
+		if sunset.IsZero() {
+			t.logger.Printf("%sendpoint %s is deprecated (%s)", logPrefix(req.Context()), endpoint, deprecationDate(deprecation))
+		} else {
+			t.logger.Printf("%sendpoint %s is deprecated and will be removed after %s", logPrefix(req.Context()), endpoint, sunset.Format(time.RFC3339))
+		}
+	}
+
+	if t.deprecationHandler != nil {
+		t.deprecationHandler(endpoint, sunset)
+	}
+}
+
+// deprecationDate renders a Deprecation header, which is either a structured date such as
+// @1688169599, an HTTP date, or the legacy value true.
+func deprecationDate(value string) string {
+	if unix, err := strconv.ParseInt(strings.TrimPrefix(value, "@"), 10, 64); err == nil && strings.HasPrefix(value, "@") {
+		return "since " + time.Unix(unix, 0).UTC().Format(time.RFC3339)
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		return "since " + date.UTC().Format(time.RFC3339)
+	}
+
+	return "no date given"
+}
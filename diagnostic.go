@@ -0,0 +1,158 @@
+package tonrocket
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const redacted = "[redacted]"
+
+// sensitiveFields are JSON keys whose values are replaced in diagnostic records, compared
+// ignoring case.
+var sensitiveFields = map[string]bool{
+	"password":      true,
+	"hiddenmessage": true,
+	"token":         true,
+	"secret":        true,
+}
+
+type diagnosticRecord struct {
+	Time       time.Time         `json:"time"`
+	Method     string            `json:"method"`
+	Path       string            `json:"path"`
+	Query      string            `json:"query,omitempty"`
+	Header     map[string]string `json:"header,omitempty"`
+	Request    json.RawMessage   `json:"request,omitempty"`
+	Status     int               `json:"status,omitempty"`
+	Response   json.RawMessage   `json:"response,omitempty"`
+	DurationMS int64             `json:"durationMs"`
+	Error      string            `json:"error,omitempty"`
+}
+
+type diagnosticLog struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (l *diagnosticLog) write(record diagnosticRecord) {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	_, _ = l.w.Write(append(data, '\n'))
+}
+
+// diagnosticMiddleware writes one record per attempt. It runs right before the HTTP client,
+// so the auth header is set and is redacted here.
+func (t *tonrocket) diagnosticMiddleware(next RoundTripFunc) RoundTripFunc {
+	return func(req *http.Request) (*http.Response, error) {
+		record := diagnosticRecord{
+			Time:   time.Now().UTC(),
+			Method: req.Method,
+			Path:   req.URL.Path,
+			Query:  req.URL.RawQuery,
+			Header: redactHeader(req.Header),
+		}
+
+		if req.GetBody != nil {
+			if body, err := req.GetBody(); err == nil {
+				data, _ := io.ReadAll(body)
+				body.Close()
+				record.Request = redactJSON(data)
+			}
+		}
+
+		resp, err := next(req)
+		record.DurationMS = time.Since(record.Time).Milliseconds()
+
+		if err != nil {
+			record.Error = err.Error()
+			t.diagnosticLog.write(record)
+			return resp, err
+		}
+
+		record.Status = resp.StatusCode
+
+		limit := t.maxResponseBytes
+		if limit <= 0 {
+			limit = DefaultMaxResponseBytes
+		}
+		data, readErr := io.ReadAll(io.LimitReader(resp.Body, limit))
+		resp.Body = readCloser{Reader: io.MultiReader(bytes.NewReader(data), resp.Body), Closer: resp.Body}
+		if readErr != nil {
+			record.Error = readErr.Error()
+		}
+		record.Response = redactJSON(data)
+
+		t.diagnosticLog.write(record)
+
+		return resp, nil
+	}
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+func redactHeader(header http.Header) map[string]string {
+	out := make(map[string]string, len(header))
+	for name := range header {
+		if strings.EqualFold(name, AuthHeader) || strings.EqualFold(name, "Authorization") {
+			out[name] = redacted
+			continue
+		}
+		out[name] = header.Get(name)
+	}
+
+	return out
+}
+
+// redactJSON returns data with sensitive fields replaced, or data as a JSON string if it is not
+// valid JSON.
+func redactJSON(data []byte) json.RawMessage {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil
+	}
+
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		quoted, _ := json.Marshal(string(data))
+		return quoted
+	}
+
+	out, err := json.Marshal(redactValue(value))
+	if err != nil {
+		return nil
+	}
+
+	return out
+}
+
+func redactValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, field := range v {
+			if sensitiveFields[strings.ToLower(key)] {
+				v[key] = redacted
+				continue
+			}
+			v[key] = redactValue(field)
+		}
+	case []any:
+		for i := range v {
+			v[i] = redactValue(v[i])
+		}
+	}
+
+	return value
+}
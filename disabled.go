@@ -0,0 +1,178 @@
+package tonrocket
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+var ErrPaymentsDisabled = errors.New("payments are disabled")
+
+type disabled struct {
+	zeroReads bool
+}
+
+// Disabled returns a Tonrocket that never touches the network, for use behind a feature
+// flag. Every method that would call the API returns ErrPaymentsDisabled. Local methods
+// (RateLimitStatus, Config, DisplayTime, FormatTime, CurrencyLimits, CurrenciesFor,
+// CheckMinimum, NetAmount, GrossAmount, Now, IsExpired, TimeUntilExpiry) return their usual
+// values, the currency data comes from the embedded snapshot and Now is never corrected.
+func Disabled() Tonrocket {
+	return &disabled{}
+}
+
+// DisabledWithZeroReads is like Disabled, except that read methods (GetInvoice, ListInvoices,
+// InvoiceCount, AppInfo, WithdrawalFees, AvailableCurrencies, GetMultiCheque, ServerTime,
+// ClockOffset) return empty results and no error. Methods that create invoices or move funds
+// still return ErrPaymentsDisabled, so a disabled payout is never mistaken for a successful one.
+func DisabledWithZeroReads() Tonrocket {
+	return &disabled{zeroReads: true}
+}
+
+func (d *disabled) readErr() error {
+	if d.zeroReads {
+		return nil
+	}
+
+	return ErrPaymentsDisabled
+}
+
+func (d *disabled) GetInvoice(context.Context, string) (*Invoice, error) {
+	return &Invoice{}, d.readErr()
+}
+
+func (d *disabled) ListInvoices(context.Context, int, int) ([]*Invoice, int, error) {
+	return nil, 0, d.readErr()
+}
+
+func (d *disabled) InvoiceCount(context.Context) (int, error) {
+	return 0, d.readErr()
+}
+
+func (d *disabled) CreateInvoice(context.Context, CreateInvoiceRequest) (*Invoice, error) {
+	return nil, ErrPaymentsDisabled
+}
+
+func (d *disabled) CreateTransfer(context.Context, CreateTransferRequest) (*Transfer, error) {
+	return nil, ErrPaymentsDisabled
+}
+
+func (d *disabled) CreateWithdrawal(context.Context, CreateWithdrawalRequest) (*Withdrawal, error) {
+	return nil, ErrPaymentsDisabled
+}
+
+func (d *disabled) AppInfo(context.Context) (*AppInfo, error) {
+	return &AppInfo{}, d.readErr()
+}
+
+func (d *disabled) WithdrawalFees(context.Context) ([]*WithdrawalFees, error) {
+	return nil, d.readErr()
+}
+
+func (d *disabled) AvailableCurrencies(context.Context) ([]*CurrencyInfo, error) {
+	return nil, d.readErr()
+}
+
+func (d *disabled) GetMultiCheque(context.Context, int64) (*MultiCheque, error) {
+	return &MultiCheque{}, d.readErr()
+}
+
+func (d *disabled) CurrencyLimits(currency Currency) (*CurrencyInfo, bool) {
+	return findCurrency(loadEmbeddedCurrencies(), currency)
+}
+
+func (d *disabled) CurrenciesFor(op Operation) []*CurrencyInfo {
+	return currenciesFor(loadEmbeddedCurrencies(), op)
+}
+
+func (d *disabled) CheckMinimum(op Operation, currency Currency, amount decimal.Decimal) error {
+	info, ok := d.CurrencyLimits(currency)
+	if !ok {
+		return nil
+	}
+
+	return checkMinimum(info, op, amount)
+}
+
+func (d *disabled) WaitForPayment(context.Context, string, time.Duration) (*Invoice, error) {
+	return nil, ErrPaymentsDisabled
+}
+
+func (d *disabled) CreateInvoiceAndWait(context.Context, CreateInvoiceRequest, time.Duration) (*Invoice, error) {
+	return nil, ErrPaymentsDisabled
+}
+
+func (d *disabled) CreateInvoices(_ context.Context, reqs []CreateInvoiceRequest, _ int) ([]*Invoice, error) {
+	errs := make([]error, len(reqs))
+	for i := range errs {
+		errs[i] = ErrPaymentsDisabled
+	}
+
+	return make([]*Invoice, len(reqs)), NewMultiError(errs)
+}
+
+func (d *disabled) CreateInvoiceForSKU(context.Context, string, Currency) (*Invoice, error) {
+	return nil, ErrPaymentsDisabled
+}
+
+func (d *disabled) ServerTime(context.Context) (time.Time, error) {
+	return time.Now(), d.readErr()
+}
+
+func (d *disabled) ClockOffset(context.Context) (time.Duration, error) {
+	return 0, d.readErr()
+}
+
+func (d *disabled) Now() time.Time {
+	return time.Now()
+}
+
+func (d *disabled) IsExpired(inv *Invoice) bool {
+	return inv.IsExpiredAt(time.Now())
+}
+
+func (d *disabled) TimeUntilExpiry(inv *Invoice) (time.Duration, bool) {
+	return inv.TimeUntilExpiry(time.Now())
+}
+
+func (d *disabled) RateLimitStatus() RateLimitStatus {
+	return RateLimitStatus{}
+}
+
+func (d *disabled) SetRateLimit(float64, int) {}
+
+func (d *disabled) SetMaxConcurrency(int) {}
+
+func (d *disabled) WatchBalances(context.Context, time.Duration) (<-chan []Balance, error) {
+	return nil, ErrPaymentsDisabled
+}
+
+func (d *disabled) WaitForBalance(context.Context, Currency, decimal.Decimal, time.Duration) error {
+	return ErrPaymentsDisabled
+}
+
+func (d *disabled) Config() ClientConfig {
+	return ClientConfig{Environment: "disabled"}
+}
+
+func (d *disabled) CircuitState() CircuitState {
+	return CircuitClosed
+}
+
+func (d *disabled) NetAmount(currency Currency, gross, feePct decimal.Decimal) decimal.Decimal {
+	return (&tonrocket{}).NetAmount(currency, gross, feePct)
+}
+
+func (d *disabled) GrossAmount(currency Currency, net, feePct decimal.Decimal) decimal.Decimal {
+	return (&tonrocket{}).GrossAmount(currency, net, feePct)
+}
+
+func (d *disabled) DisplayTime(ts time.Time) time.Time {
+	return ts.UTC()
+}
+
+func (d *disabled) FormatTime(ts time.Time, layout string) string {
+	return ts.UTC().Format(layout)
+}
@@ -0,0 +1,45 @@
+package tonrocket
+
+// Endpoints holds the API paths the client calls, relative to the base URL.
+type Endpoints struct {
+	AppInfo        string
+	Transfer       string
+	Invoices       string
+	Withdrawal     string
+	WithdrawalFees string
+	Currencies     string
+	Version        string
+	MultiCheques   string
+}
+
+var DefaultEndpoints = Endpoints{
+	AppInfo:        "/app/info",
+	Transfer:       "/app/transfer",
+	Invoices:       "/tg-invoices",
+	Withdrawal:     "/app/withdrawal",
+	WithdrawalFees: "/app/withdrawal/fees",
+	Currencies:     "/currencies/available",
+	Version:        "/version",
+	MultiCheques:   "/multi-cheque",
+}
+
+// merge returns e with empty paths replaced by the ones in defaults.
+func (e Endpoints) merge(defaults Endpoints) Endpoints {
+	pick := func(path, fallback string) string {
+		if path == "" {
+			return fallback
+		}
+		return path
+	}
+
+	return Endpoints{
+		AppInfo:        pick(e.AppInfo, defaults.AppInfo),
+		Transfer:       pick(e.Transfer, defaults.Transfer),
+		Invoices:       pick(e.Invoices, defaults.Invoices),
+		Withdrawal:     pick(e.Withdrawal, defaults.Withdrawal),
+		WithdrawalFees: pick(e.WithdrawalFees, defaults.WithdrawalFees),
+		Currencies:     pick(e.Currencies, defaults.Currencies),
+		Version:        pick(e.Version, defaults.Version),
+		MultiCheques:   pick(e.MultiCheques, defaults.MultiCheques),
+	}
+}
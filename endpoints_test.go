@@ -0,0 +1,26 @@
+package tonrocket
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestWithEndpoints(t *testing.T) {
+	var paths []string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		writeData(w, AppInfo{Name: "app"})
+	}, WithEndpoints(Endpoints{AppInfo: "/v2/app/info"}))
+
+	if _, err := client.AppInfo(context.Background()); err != nil {
+		t.Fatalf("AppInfo: %v", err)
+	}
+	if len(paths) != 1 || paths[0] != "/v2/app/info" {
+		t.Fatalf("paths = %v, want [/v2/app/info]", paths)
+	}
+
+	if got := client.Config().Endpoints.Transfer; got != DefaultEndpoints.Transfer {
+		t.Fatalf("Transfer = %q, want the default %q", got, DefaultEndpoints.Transfer)
+	}
+}
@@ -0,0 +1,80 @@
+package tonrocket
+
+import (
+	"net/http"
+	"time"
+)
+
+// ExpiresAt returns when the invoice expires, Created plus ExpiredIn. It is false if the
+// invoice has no expiry.
+func (i *Invoice) ExpiresAt() (time.Time, bool) {
+	if i.ExpiredIn <= 0 {
+		return time.Time{}, false
+	}
+
+	return i.Created.Add(time.Duration(i.ExpiredIn) * time.Second), true
+}
+
+// IsExpiredAt reports whether the invoice is expired at now, by status or by its expiry time.
+func (i *Invoice) IsExpiredAt(now time.Time) bool {
+	if i.Status == InvoiceExpired {
+		return true
+	}
+
+	expiresAt, ok := i.ExpiresAt()
+
+	return ok && !now.Before(expiresAt)
+}
+
+// TimeUntilExpiry returns how long after now the invoice expires, negative once it has. It is
+// false if the invoice has no expiry.
+func (i *Invoice) TimeUntilExpiry(now time.Time) (time.Duration, bool) {
+	expiresAt, ok := i.ExpiresAt()
+	if !ok {
+		return 0, false
+	}
+
+	return expiresAt.Sub(now), true
+}
+
+// Now returns the local time, corrected by the measured server clock offset when
+// WithClockOffsetCorrection is set. The offset is taken from the Date header of every API
+// response and from ClockOffset calls, until the first one it is zero.
+func (t *tonrocket) Now() time.Time {
+	now := time.Now()
+	if !t.clockCorrection {
+		return now
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return now.Add(t.clockOffset)
+}
+
+// IsExpired reports whether inv is expired at Now.
+func (t *tonrocket) IsExpired(inv *Invoice) bool {
+	return inv.IsExpiredAt(t.Now())
+}
+
+// TimeUntilExpiry returns how long after Now inv expires, see Invoice.TimeUntilExpiry.
+func (t *tonrocket) TimeUntilExpiry(inv *Invoice) (time.Duration, bool) {
+	return inv.TimeUntilExpiry(t.Now())
+}
+
+func (t *tonrocket) setClockOffset(offset time.Duration) {
+	t.mu.Lock()
+	t.clockOffset = offset
+	t.mu.Unlock()
+}
+
+// updateClockOffset measures the offset from a response Date header, taking the local time at
+// the middle of the round trip that started at start.
+func (t *tonrocket) updateClockOffset(header http.Header, start time.Time) {
+	serverTime, err := http.ParseTime(header.Get("Date"))
+	if err != nil {
+		return
+	}
+
+	t.setClockOffset(serverTime.Sub(start.Add(time.Since(start) / 2)))
+}
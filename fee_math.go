@@ -0,0 +1,49 @@
+package tonrocket
+
+import "github.com/shopspring/decimal"
+
+// feeMathGuardDigits is how many digits beyond the currency decimals the fee division keeps
+// unless WithFeeMathPrecision is set.
+const feeMathGuardDigits = 8
+
+var hundred = decimal.NewFromInt(100)
+
+// feeDivisionPrecision returns the places the fee division keeps, precision if it is set.
+func feeDivisionPrecision(currency Currency, precision int32) int32 {
+	if precision > 0 {
+		return precision
+	}
+
+	return currency.Decimals() + feeMathGuardDigits
+}
+
+// netAmount is the fee math behind NetAmount and Invoice.NetReceived, precision zero keeps
+// the default guard digits.
+func netAmount(currency Currency, gross, feePct decimal.Decimal, precision int32) decimal.Decimal {
+	fee := gross.Mul(feePct).DivRound(hundred, feeDivisionPrecision(currency, precision))
+
+	return gross.Sub(fee).RoundFloor(currency.Decimals())
+}
+
+func grossAmount(currency Currency, net, feePct decimal.Decimal, precision int32) decimal.Decimal {
+	share := hundred.Sub(feePct)
+	if !share.IsPositive() {
+		return decimal.Zero
+	}
+
+	gross := net.Mul(hundred).DivRound(share, feeDivisionPrecision(currency, precision))
+
+	return gross.RoundCeil(currency.Decimals())
+}
+
+// NetAmount returns what is left of gross after a fee of feePct percent. The result is
+// rounded down to the currency decimals, so it never overstates what the app is credited.
+func (t *tonrocket) NetAmount(currency Currency, gross, feePct decimal.Decimal) decimal.Decimal {
+	return netAmount(currency, gross, feePct, t.feeMathPrecision)
+}
+
+// GrossAmount returns the amount to charge so that net is left after a fee of feePct percent.
+// The result is rounded up to the currency decimals, so the net is always covered.
+func (t *tonrocket) GrossAmount(currency Currency, net, feePct decimal.Decimal) decimal.Decimal {
+	return grossAmount(currency, net, feePct, t.feeMathPrecision)
+}
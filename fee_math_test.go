@@ -0,0 +1,37 @@
+package tonrocket
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestInvoiceFeeMatchesNetAmount(t *testing.T) {
+	client := NewTonrocket("token")
+
+	tests := []struct {
+		amount, feePct string
+		activations    int
+	}{
+		{"10", "1.5", 1},
+		{"0.333333333", "2.5", 3},
+		{"1", "0", 1},
+	}
+
+	for _, tt := range tests {
+		inv := &Invoice{
+			Amount:           decimal.RequireFromString(tt.amount),
+			Currency:         TONCurrency,
+			TotalActivations: tt.activations,
+		}
+		feePct := decimal.RequireFromString(tt.feePct)
+
+		want := client.NetAmount(TONCurrency, inv.PaidAmount(), feePct)
+		if got := inv.NetReceived(feePct); !got.Equal(want) {
+			t.Errorf("%s x%d at %s%%: NetReceived = %s, NetAmount = %s", tt.amount, tt.activations, tt.feePct, got, want)
+		}
+		if sum := inv.NetReceived(feePct).Add(inv.Fee(feePct)); !sum.Equal(inv.PaidAmount()) {
+			t.Errorf("%s x%d at %s%%: net + fee = %s, paid = %s", tt.amount, tt.activations, tt.feePct, sum, inv.PaidAmount())
+		}
+	}
+}
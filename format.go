@@ -0,0 +1,68 @@
+package tonrocket
+
+import (
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+const defaultDecimals = 9
+
+var currencyDecimals = map[Currency]int32{
+	TONCurrency: 9,
+	"USDT":      6,
+	"BTC":       8,
+}
+
+// Decimals returns the number of fractional digits used for the currency. Jettons not in
+// the table are assumed to use 9, like TON.
+func (c Currency) Decimals() int32 {
+	if d, ok := currencyDecimals[c]; ok {
+		return d
+	}
+
+	return defaultDecimals
+}
+
+type AmountFormat struct {
+	ThousandsSeparator string
+	DecimalSeparator   string
+}
+
+var DefaultAmountFormat = AmountFormat{
+	ThousandsSeparator: ",",
+	DecimalSeparator:   ".",
+}
+
+// FormatAmount formats the amount with the currency precision followed by the currency
+// name, e.g. "1,234.500000000 TON".
+func FormatAmount(amount decimal.Decimal, currency Currency) string {
+	return FormatAmountLocale(amount, currency, DefaultAmountFormat)
+}
+
+func FormatAmountLocale(amount decimal.Decimal, currency Currency, format AmountFormat) string {
+	fixed := amount.StringFixed(currency.Decimals())
+
+	sign := ""
+	if strings.HasPrefix(fixed, "-") {
+		sign, fixed = "-", fixed[1:]
+	}
+
+	integer, fraction, _ := strings.Cut(fixed, ".")
+
+	var b strings.Builder
+	b.WriteString(sign)
+	for i, digit := range integer {
+		if i > 0 && (len(integer)-i)%3 == 0 {
+			b.WriteString(format.ThousandsSeparator)
+		}
+		b.WriteRune(digit)
+	}
+
+	if fraction != "" {
+		b.WriteString(format.DecimalSeparator)
+		b.WriteString(fraction)
+	}
+
+	return b.String() + " " + currency.String()
+}
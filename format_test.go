@@ -0,0 +1,37 @@
+package tonrocket
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestFormatAmountLocale(t *testing.T) {
+	var (
+		german = AmountFormat{ThousandsSeparator: ".", DecimalSeparator: ","}
+		french = AmountFormat{ThousandsSeparator: " ", DecimalSeparator: ","}
+		swiss  = AmountFormat{ThousandsSeparator: "'", DecimalSeparator: "."}
+	)
+
+	tests := []struct {
+		amount   string
+		currency Currency
+		format   AmountFormat
+		want     string
+	}{
+		{"1234.56", "USDT", german, "1.234,560000 USDT"},
+		{"1234567.5", "BTC", french, "1 234 567,50000000 BTC"},
+		{"-1234567.5", "BTC", swiss, "-1'234'567.50000000 BTC"},
+		{"999.1", "USDT", german, "999,100000 USDT"},
+		{"0.5", TONCurrency, DefaultAmountFormat, "0.500000000 TON"},
+		{"1000", TONCurrency, DefaultAmountFormat, "1,000.000000000 TON"},
+		{"0.0000001234", "USDT", german, "0,000000 USDT"},
+	}
+
+	for _, tt := range tests {
+		got := FormatAmountLocale(decimal.RequireFromString(tt.amount), tt.currency, tt.format)
+		if got != tt.want {
+			t.Errorf("FormatAmountLocale(%s, %s) = %q, want %q", tt.amount, tt.currency, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package tonrocket
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+)
+
+type idempotentRequestKey struct{}
+
+// markIdempotent flags a POST whose body carries an id the server deduplicates by, so it
+// is safe to send twice.
+func markIdempotent(ctx context.Context) context.Context {
+	return context.WithValue(ctx, idempotentRequestKey{}, true)
+}
+
+func isIdempotent(req *http.Request) bool {
+	if req.Method == http.MethodGet || req.Method == http.MethodHead {
+		return true
+	}
+
+	marked, _ := req.Context().Value(idempotentRequestKey{}).(bool)
+
+	return marked && req.GetBody != nil
+}
+
+type hedgeResult struct {
+	attempt int
+	resp    *http.Response
+	err     error
+}
+
+// hedgingMiddleware sends a second copy of an idempotent request when the first has not
+// answered within delay, and returns whichever succeeds first. The other one is cancelled.
+func (t *tonrocket) hedgingMiddleware(next RoundTripFunc) RoundTripFunc {
+	return func(req *http.Request) (*http.Response, error) {
+		if !isIdempotent(req) {
+			return next(req)
+		}
+
+		results := make(chan hedgeResult, 2)
+		var cancels []context.CancelFunc
+
+		launch := func(r *http.Request) {
+			ctx, cancel := context.WithCancel(req.Context())
+			attempt := len(cancels)
+			cancels = append(cancels, cancel)
+
+			go func() {
+				resp, err := next(r.Clone(ctx))
+				results <- hedgeResult{attempt: attempt, resp: resp, err: err}
+			}()
+		}
+
+		launch(req)
+
+		hedge := time.NewTimer(t.hedgeDelay)
+		defer hedge.Stop()
+
+		inFlight := 1
+		for {
+			select {
+			case <-hedge.C:
+				second := req.Clone(req.Context())
+				if req.GetBody != nil {
+					body, err := req.GetBody()
+					if err != nil {
+						continue
+					}
+					second.Body = body
+				}
+				launch(second)
+				inFlight++
+			case res := <-results:
+				inFlight--
+
+				if res.err == nil {
+					for i, cancel := range cancels {
+						if i != res.attempt {
+							cancel()
+						}
+					}
+					if inFlight > 0 {
+						go discardLoser(results)
+					}
+					res.resp.Body = &cancelOnClose{ReadCloser: res.resp.Body, cancel: cancels[res.attempt]}
+					return res.resp, nil
+				}
+
+				cancels[res.attempt]()
+				if inFlight == 0 {
+					return nil, res.err
+				}
+			}
+		}
+	}
+}
+
+// discardLoser waits for the cancelled attempt and closes its response.
+func discardLoser(results <-chan hedgeResult) {
+	if res := <-results; res.resp != nil {
+		res.resp.Body.Close()
+	}
+}
+
+// cancelOnClose keeps the winning attempt's context alive until its body is closed.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnClose) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+
+	return err
+}
@@ -0,0 +1,48 @@
+package tonrocket
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestHedgingSlowFirstAttempt(t *testing.T) {
+	var attempts atomic.Int32
+	loserCancelled := make(chan struct{})
+
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) == 1 {
+			select {
+			case <-r.Context().Done():
+				close(loserCancelled)
+			case <-time.After(2 * time.Second):
+			}
+			return
+		}
+		writeData(w, AppInfo{Name: "hedge"})
+	}, WithHedging(20*time.Millisecond))
+
+	if got := client.Config().HedgeDelay; got != 20*time.Millisecond {
+		t.Fatalf("HedgeDelay = %v", got)
+	}
+
+	start := time.Now()
+	info, err := client.AppInfo(context.Background())
+	if err != nil {
+		t.Fatalf("AppInfo: %v", err)
+	}
+	if info.Name != "hedge" {
+		t.Fatalf("name = %q, want the hedged response", info.Name)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("took %v, the hedge did not win", elapsed)
+	}
+
+	select {
+	case <-loserCancelled:
+	case <-time.After(time.Second):
+		t.Fatal("slow attempt was not cancelled")
+	}
+}
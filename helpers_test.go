@@ -0,0 +1,45 @@
+package tonrocket
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newTestClient returns a client whose requests, whatever their base URL, are served by
+// handler.
+func newTestClient(t *testing.T, handler http.HandlerFunc, opts ...Option) *tonrocket {
+	t.Helper()
+
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	redirect := func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			req.URL.Scheme = "http"
+			req.URL.Host = srv.Listener.Addr().String()
+			return next(req)
+		}
+	}
+
+	return NewTonrocket("token", append([]Option{WithMiddleware(redirect)}, opts...)...).(*tonrocket)
+}
+
+// writeData writes a successful API envelope carrying data.
+func writeData(w http.ResponseWriter, data any) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		panic(err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(response{Success: true, Data: raw})
+}
+
+// writeError writes a failed API envelope with the given status.
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(response{Message: message})
+}
@@ -0,0 +1,133 @@
+package tonrocket
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"unicode/utf8"
+)
+
+// ErrIdempotencyConflict is returned, with WithIdempotencyConflictDetection, when an
+// idempotency key or transfer id is reused for a different request.
+var ErrIdempotencyConflict = errors.New("idempotency key reused with a different request")
+
+// maxTransferHashes bounds how many transfer ids are remembered for conflict detection.
+const maxTransferHashes = 10000
+
+type idempotencyKey struct{}
+
+// WithIdempotencyKey returns a context carrying an idempotency key for the request made with it.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKey{}, key)
+}
+
+func IdempotencyKey(ctx context.Context) string {
+	key, _ := ctx.Value(idempotencyKey{}).(string)
+
+	return key
+}
+
+func embedIdempotencyKey(payload, key, hash string) (string, error) {
+	envelope, ok := parsePayloadEnvelope(payload)
+	if !ok {
+		envelope = &payloadEnvelope{Payload: payload}
+	}
+	envelope.IdempotencyKey = key
+	envelope.RequestHash = hash
+
+	data, err := json.Marshal(envelope)
+	if err != nil {
+		return "", err
+	}
+
+	if n := utf8.RuneCount(data); n > MaxPayloadLength {
+		return "", fmt.Errorf("%w: %d characters, limit is %d", ErrPayloadTooLong, n, MaxPayloadLength)
+	}
+
+	return string(data), nil
+}
+
+func (t *tonrocket) findInvoiceByIdempotencyKey(ctx context.Context, key string) (*Invoice, error) {
+	invoices, _, err := t.ListInvoices(ctx, t.payloadIdempotency, 0)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list invoices for idempotency check: %w", err)
+	}
+
+	for _, inv := range invoices {
+		if envelope, ok := parsePayloadEnvelope(inv.Payload); ok && envelope.IdempotencyKey == key {
+			return inv, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func requestHash(req any) string {
+	data, _ := json.Marshal(req)
+	sum := sha256.Sum256(data)
+
+	return hex.EncodeToString(sum[:16])
+}
+
+// checkInvoiceConflict returns ErrIdempotencyConflict if existing was created from a request
+// with another hash. Invoices created before hashes were stored are not checked.
+func (t *tonrocket) checkInvoiceConflict(existing *Invoice, hash string) error {
+	if !t.idempotencyConflicts {
+		return nil
+	}
+
+	envelope, ok := parsePayloadEnvelope(existing.Payload)
+	if ok && envelope.RequestHash != "" && envelope.RequestHash != hash {
+		return fmt.Errorf("%w: invoice %s", ErrIdempotencyConflict, existing.ID.String())
+	}
+
+	return nil
+}
+
+// checkTransferConflict returns ErrIdempotencyConflict if the transfer id was sent successfully
+// by this client with a different request, and the hash to remember once this one succeeds.
+func (t *tonrocket) checkTransferConflict(req CreateTransferRequest) (string, error) {
+	if !t.idempotencyConflicts {
+		return "", nil
+	}
+
+	hash := requestHash(req)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if previous, ok := t.transferHashes[req.TransferID]; ok && previous != hash {
+		return "", fmt.Errorf("%w: transfer %s", ErrIdempotencyConflict, req.TransferID)
+	}
+
+	return hash, nil
+}
+
+// rememberTransfer records the hash of a transfer the API accepted. Failed sends are not
+// remembered, so a corrected request can reuse the id. Only the most recent maxTransferHashes
+// ids are kept, and nothing survives a restart.
+func (t *tonrocket) rememberTransfer(id, hash string) {
+	if hash == "" {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, ok := t.transferHashes[id]; ok {
+		return
+	}
+
+	if t.transferHashes == nil {
+		t.transferHashes = make(map[string]string)
+	}
+	if len(t.transferOrder) >= maxTransferHashes {
+		delete(t.transferHashes, t.transferOrder[0])
+		t.transferOrder = t.transferOrder[1:]
+	}
+	t.transferHashes[id] = hash
+	t.transferOrder = append(t.transferOrder, id)
+}
@@ -0,0 +1,59 @@
+package tonrocket
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestTransferIdempotencyConflict(t *testing.T) {
+	transfer := func(amount string) CreateTransferRequest {
+		return CreateTransferRequest{
+			TransferID: "payout-1",
+			TgUserID:   42,
+			Currency:   TONCurrency,
+			Amount:     decimal.RequireFromString(amount),
+		}
+	}
+
+	tests := []struct {
+		name         string
+		firstStatus  int
+		wantConflict bool
+		wantRequests int32
+	}{
+		{"reuse after success", http.StatusOK, true, 1},
+		{"reuse after failure", http.StatusBadRequest, false, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests atomic.Int32
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if requests.Add(1) == 1 && tt.firstStatus != http.StatusOK {
+					writeError(w, tt.firstStatus, "rejected")
+					return
+				}
+				writeData(w, Transfer{ID: 1, TransferID: "payout-1"})
+			}, WithIdempotencyConflictDetection())
+
+			_, _ = client.CreateTransfer(context.Background(), transfer("1"))
+
+			_, err := client.CreateTransfer(context.Background(), transfer("2"))
+			if got := errors.Is(err, ErrIdempotencyConflict); got != tt.wantConflict {
+				t.Fatalf("conflict = %v, want %v (err %v)", got, tt.wantConflict, err)
+			}
+			if got := requests.Load(); got != tt.wantRequests {
+				t.Fatalf("requests = %d, want %d", got, tt.wantRequests)
+			}
+
+			if _, err := client.CreateTransfer(context.Background(), transfer("1")); tt.wantConflict && err != nil {
+				t.Fatalf("same request after success: %v", err)
+			}
+		})
+	}
+}
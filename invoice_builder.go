@@ -0,0 +1,135 @@
+package tonrocket
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"unicode/utf8"
+)
+
+// MaxPayloadLength is the maximum number of characters Rocket accepts in the invoice payload.
+const MaxPayloadLength = 4000
+
+var ErrPayloadTooLong = errors.New("payload exceeds the field limit")
+
+// payloadEnvelope is the JSON shape stored in the payload when metadata is attached.
+type payloadEnvelope struct {
+	Payload        string            `json:"payload,omitempty"`
+	Metadata       map[string]string `json:"tonrocket:metadata,omitempty"`
+	IdempotencyKey string            `json:"tonrocket:idempotency,omitempty"`
+	RequestHash    string            `json:"tonrocket:hash,omitempty"`
+}
+
+type InvoiceBuilder struct {
+	req      CreateInvoiceRequest
+	metadata map[string]string
+}
+
+func NewInvoice(amount float64, currency Currency) *InvoiceBuilder {
+	return &InvoiceBuilder{
+		req: CreateInvoiceRequest{
+			Amount:      amount,
+			Currency:    currency,
+			NumPayments: 1,
+		},
+	}
+}
+
+func (b *InvoiceBuilder) WithMinPayment(amount float64) *InvoiceBuilder {
+	b.req.MinPayment = amount
+	return b
+}
+
+func (b *InvoiceBuilder) WithNumPayments(n int) *InvoiceBuilder {
+	b.req.NumPayments = n
+	return b
+}
+
+func (b *InvoiceBuilder) WithDescription(description string) *InvoiceBuilder {
+	b.req.Description = description
+	return b
+}
+
+func (b *InvoiceBuilder) WithHiddenMessage(message string) *InvoiceBuilder {
+	b.req.HiddenMessage = message
+	return b
+}
+
+func (b *InvoiceBuilder) WithCallbackURL(callbackURL string) *InvoiceBuilder {
+	b.req.CallbackURL = callbackURL
+	return b
+}
+
+func (b *InvoiceBuilder) WithPayload(payload string) *InvoiceBuilder {
+	b.req.Payload = payload
+	return b
+}
+
+func (b *InvoiceBuilder) WithExpiredIn(seconds int) *InvoiceBuilder {
+	b.req.ExpiredIn = seconds
+	return b
+}
+
+// WithMetadata attaches key/value metadata that is stored in the payload under a
+// namespaced key and can be read back with Invoice.Metadata.
+func (b *InvoiceBuilder) WithMetadata(metadata map[string]string) *InvoiceBuilder {
+	b.metadata = make(map[string]string, len(metadata))
+	for k, v := range metadata {
+		b.metadata[k] = v
+	}
+	return b
+}
+
+func (b *InvoiceBuilder) Build() (CreateInvoiceRequest, error) {
+	req := b.req
+
+	if len(b.metadata) > 0 {
+		payload, err := json.Marshal(payloadEnvelope{
+			Payload:  req.Payload,
+			Metadata: b.metadata,
+		})
+		if err != nil {
+			return req, err
+		}
+		req.Payload = string(payload)
+	}
+
+	if n := utf8.RuneCountInString(req.Payload); n > MaxPayloadLength {
+		return req, fmt.Errorf("%w: %d characters, limit is %d", ErrPayloadTooLong, n, MaxPayloadLength)
+	}
+
+	return req, nil
+}
+
+// Metadata returns the metadata attached with InvoiceBuilder.WithMetadata, or nil if there is none.
+func (i *Invoice) Metadata() map[string]string {
+	envelope, ok := parsePayloadEnvelope(i.Payload)
+	if !ok {
+		return nil
+	}
+
+	return envelope.Metadata
+}
+
+// UserPayload returns the payload as it was passed to the builder, without the metadata envelope.
+func (i *Invoice) UserPayload() string {
+	envelope, ok := parsePayloadEnvelope(i.Payload)
+	if !ok {
+		return i.Payload
+	}
+
+	return envelope.Payload
+}
+
+func parsePayloadEnvelope(payload string) (*payloadEnvelope, bool) {
+	var envelope payloadEnvelope
+	if err := json.Unmarshal([]byte(payload), &envelope); err != nil {
+		return nil, false
+	}
+
+	if envelope.Metadata == nil && envelope.IdempotencyKey == "" {
+		return nil, false
+	}
+
+	return &envelope, true
+}
@@ -0,0 +1,39 @@
+package tonrocket
+
+import (
+	"math/rand"
+	"time"
+)
+
+// DefaultPollJitter is the fraction the polling helpers randomly vary their interval by, so
+// loops started together do not hit the API in bursts.
+const DefaultPollJitter = 0.2
+
+type pollJitter struct {
+	fraction float64
+	rnd      func() float64
+}
+
+var defaultPollJitter = pollJitter{fraction: DefaultPollJitter, rnd: rand.Float64}
+
+// apply returns d varied by up to ±fraction, never below MinPollInterval.
+func (j pollJitter) apply(d time.Duration) time.Duration {
+	if j.fraction <= 0 {
+		return d
+	}
+
+	d += time.Duration((j.rnd()*2 - 1) * j.fraction * float64(d))
+	if d < MinPollInterval {
+		d = MinPollInterval
+	}
+
+	return d
+}
+
+func newPollJitter(fraction float64, rnd func() float64) pollJitter {
+	if rnd == nil {
+		rnd = rand.Float64
+	}
+
+	return pollJitter{fraction: fraction, rnd: rnd}
+}
@@ -0,0 +1,107 @@
+package tonrocket
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// criticalFields are decoded strictly even in lenient mode, a response without a usable
+// id, amount or status is not worth returning.
+var criticalFields = map[string]bool{
+	"id":     true,
+	"amount": true,
+	"status": true,
+}
+
+var unmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// lenientUnmarshal decodes data into target field by field. A non-critical field that fails
+// to decode is left at its zero value and reported in warnings.
+func lenientUnmarshal(data []byte, target any, warnings *[]string) error {
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Pointer || v.IsNil() {
+		return json.Unmarshal(data, target)
+	}
+
+	return lenientValue(data, v.Elem(), "", warnings)
+}
+
+func lenientValue(data []byte, v reflect.Value, path string, warnings *[]string) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	if v.Addr().Type().Implements(unmarshalerType) {
+		return json.Unmarshal(data, v.Addr().Interface())
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return lenientValue(data, v.Elem(), path, warnings)
+	case reflect.Slice:
+		var items []json.RawMessage
+		if err := json.Unmarshal(data, &items); err != nil {
+			return err
+		}
+		v.Set(reflect.MakeSlice(v.Type(), len(items), len(items)))
+		for i, item := range items {
+			if err := lenientValue(item, v.Index(i), fmt.Sprintf("%s[%d]", path, i), warnings); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Struct:
+		return lenientStruct(data, v, path, warnings)
+	}
+
+	return json.Unmarshal(data, v.Addr().Interface())
+}
+
+func lenientStruct(data []byte, v reflect.Value, path string, warnings *[]string) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	typ := v.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		raw, ok := fields[name]
+		if !ok {
+			continue
+		}
+
+		fieldPath := name
+		if path != "" {
+			fieldPath = path + "." + name
+		}
+
+		if err := lenientValue(raw, v.Field(i), fieldPath, warnings); err != nil {
+			if criticalFields[name] {
+				return fmt.Errorf("unable to decode %s: %w", fieldPath, err)
+			}
+
+			v.Field(i).Set(reflect.Zero(f.Type))
+			*warnings = append(*warnings, fmt.Sprintf("skipped %s: %v", fieldPath, err))
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,184 @@
+package tonrocket
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// limiter throttles requests to a rate, with a token bucket, and to a number in flight. Both
+// limits can change at runtime, a change applies to requests that have not started yet.
+type limiter struct {
+	mu sync.Mutex
+
+	rps    float64
+	burst  int
+	tokens float64
+	last   time.Time
+
+	maxConcurrency int
+	inFlight       int
+	waiters        []chan struct{}
+}
+
+func (l *limiter) setRate(rps float64, burst int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.refill(time.Now())
+	if burst < 1 {
+		burst = 1
+	}
+	if l.rps <= 0 {
+		l.tokens = float64(burst)
+	}
+	l.rps, l.burst = rps, burst
+	if l.tokens > float64(burst) {
+		l.tokens = float64(burst)
+	}
+}
+
+// refill adds the tokens accrued since the last call. l.mu must be held.
+func (l *limiter) refill(now time.Time) {
+	if l.rps > 0 && !l.last.IsZero() {
+		l.tokens += now.Sub(l.last).Seconds() * l.rps
+		if l.tokens > float64(l.burst) {
+			l.tokens = float64(l.burst)
+		}
+	}
+	l.last = now
+}
+
+func (l *limiter) wait(ctx context.Context) error {
+	for {
+		l.mu.Lock()
+		if l.rps <= 0 {
+			l.mu.Unlock()
+			return nil
+		}
+
+		l.refill(time.Now())
+		if l.tokens >= 1 {
+			l.tokens--
+			l.mu.Unlock()
+			return nil
+		}
+		delay := time.Duration((1 - l.tokens) / l.rps * float64(time.Second))
+		l.mu.Unlock()
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func (l *limiter) limits() (rps float64, burst, maxConcurrency int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.rps, l.burst, l.maxConcurrency
+}
+
+func (l *limiter) setMaxConcurrency(n int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.maxConcurrency = n
+	l.wake()
+}
+
+// wake lets waiting requests start while there is room. l.mu must be held.
+func (l *limiter) wake() {
+	for len(l.waiters) > 0 && (l.maxConcurrency <= 0 || l.inFlight < l.maxConcurrency) {
+		l.inFlight++
+		close(l.waiters[0])
+		l.waiters = l.waiters[1:]
+	}
+}
+
+func (l *limiter) acquire(ctx context.Context) error {
+	l.mu.Lock()
+	if l.maxConcurrency <= 0 || l.inFlight < l.maxConcurrency {
+		l.inFlight++
+		l.mu.Unlock()
+		return nil
+	}
+
+	ready := make(chan struct{})
+	l.waiters = append(l.waiters, ready)
+	l.mu.Unlock()
+
+	select {
+	case <-ready:
+		return nil
+	case <-ctx.Done():
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for i, w := range l.waiters {
+		if w == ready {
+			l.waiters = append(l.waiters[:i], l.waiters[i+1:]...)
+			return ctx.Err()
+		}
+	}
+
+	// The slot was granted while the context ended, hand it on.
+	l.inFlight--
+	l.wake()
+
+	return ctx.Err()
+}
+
+func (l *limiter) release() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.inFlight--
+	l.wake()
+}
+
+// limiterMiddleware holds a concurrency slot until the response body is closed.
+func (t *tonrocket) limiterMiddleware(next RoundTripFunc) RoundTripFunc {
+	return func(req *http.Request) (*http.Response, error) {
+		if err := t.limiter.acquire(req.Context()); err != nil {
+			return nil, err
+		}
+
+		if err := t.limiter.wait(req.Context()); err != nil {
+			t.limiter.release()
+			return nil, err
+		}
+
+		resp, err := next(req)
+		if err != nil {
+			t.limiter.release()
+			return nil, err
+		}
+
+		var once sync.Once
+		resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: func() { once.Do(t.limiter.release) }}
+
+		return resp, nil
+	}
+}
+
+// SetRateLimit limits requests to rps per second with bursts of up to burst. A rate of zero
+// or less removes the limit. It is safe to call while requests are running, requests already
+// sent are not affected.
+func (t *tonrocket) SetRateLimit(rps float64, burst int) {
+	t.limiter.setRate(rps, burst)
+}
+
+// SetMaxConcurrency limits the number of requests in flight, a request counts until its
+// response body is closed. Zero or less removes the limit. Lowering it never interrupts
+// running requests, new ones wait until enough of them finish.
+func (t *tonrocket) SetMaxConcurrency(n int) {
+	t.limiter.setMaxConcurrency(n)
+}
@@ -0,0 +1,71 @@
+package tonrocket
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestLimiterRuntimeChanges is meant to be run with -race.
+func TestLimiterRuntimeChanges(t *testing.T) {
+	var inFlight, peak atomic.Int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		writeData(w, AppInfo{Name: "app"})
+	}, WithRateLimit(1000, 10), WithMaxConcurrency(2))
+
+	stop := make(chan struct{})
+	var tuner sync.WaitGroup
+	tuner.Add(1)
+	go func() {
+		defer tuner.Done()
+		for i := 0; ; i++ {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			client.SetRateLimit(float64(500+i%2*500), 5+i%2*5)
+			client.SetMaxConcurrency(2 + i%2*2)
+			_ = client.Config()
+			time.Sleep(time.Millisecond)
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 40; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// getRequest bypasses the AppInfo call deduplication.
+			if err := client.getRequest(context.Background(), client.endpoints.AppInfo, nil, &AppInfo{}); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	close(stop)
+	tuner.Wait()
+
+	if got := peak.Load(); got > 4 {
+		t.Fatalf("peak concurrency = %d, want at most 4", got)
+	}
+
+	client.SetRateLimit(20, 3)
+	client.SetMaxConcurrency(7)
+	cfg := client.Config()
+	if cfg.RateLimit != 20 || cfg.RateLimitBurst != 3 || cfg.MaxConcurrency != 7 {
+		t.Fatalf("config = %+v", cfg)
+	}
+}
@@ -0,0 +1,50 @@
+package tonrocket
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrMaintenance = errors.New("rocket pay is under maintenance")
+
+// MaintenanceError is returned for a 503 response that carries a Retry-After header, the
+// only maintenance signal Rocket sends. Until is the end of the window from that header.
+// It matches ErrMaintenance with errors.Is.
+type MaintenanceError struct {
+	Until time.Time
+}
+
+func (e *MaintenanceError) Error() string {
+	return fmt.Sprintf("%s until %s", ErrMaintenance, e.Until.Format(time.RFC3339))
+}
+
+func (e *MaintenanceError) Is(target error) bool {
+	return target == ErrMaintenance
+}
+
+// maintenanceUntil returns the end of the maintenance window signalled by resp, if any.
+// Retry-After is either a number of seconds or an HTTP date.
+func maintenanceUntil(resp *http.Response) (time.Time, bool) {
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		return time.Time{}, false
+	}
+
+	value := strings.TrimSpace(resp.Header.Get("Retry-After"))
+	if value == "" {
+		return time.Time{}, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Now().Add(time.Duration(seconds) * time.Second), true
+	}
+
+	if until, err := http.ParseTime(value); err == nil {
+		return until, true
+	}
+
+	return time.Time{}, false
+}
@@ -0,0 +1,53 @@
+package tonrocket
+
+import (
+	"net/http"
+	"regexp"
+	"time"
+)
+
+// Metrics receives measurements of the requests the client makes. Endpoint is the request
+// path with numeric ids replaced by ":id", so it can be used as a metric label.
+type Metrics interface {
+	RequestStarted(endpoint string)
+	RequestFinished(endpoint string, status int, err error, duration time.Duration)
+	CircuitStateChanged(state CircuitState)
+}
+
+// TaggedMetrics can be implemented by a Metrics to also receive the request tag of every
+// finished request made with WithRequestTag. Untagged requests are only reported to
+// RequestFinished.
+type TaggedMetrics interface {
+	TaggedRequestFinished(tag, endpoint string, status int, err error, duration time.Duration)
+}
+
+var idSegmentRe = regexp.MustCompile(`/[0-9]+(/|$)`)
+
+func endpointLabel(path string) string {
+	return idSegmentRe.ReplaceAllString(path, "/:id$1")
+}
+
+func (t *tonrocket) metricsMiddleware(next RoundTripFunc) RoundTripFunc {
+	return func(req *http.Request) (*http.Response, error) {
+		endpoint := endpointLabel(req.URL.Path)
+		t.metrics.RequestStarted(endpoint)
+
+		start := time.Now()
+		resp, err := next(req)
+
+		status := 0
+		if resp != nil {
+			status = resp.StatusCode
+		}
+		duration := time.Since(start)
+		t.metrics.RequestFinished(endpoint, status, err, duration)
+
+		if m, ok := t.metrics.(TaggedMetrics); ok {
+			if tag := RequestTag(req.Context()); tag != "" {
+				m.TaggedRequestFinished(tag, endpoint, status, err, duration)
+			}
+		}
+
+		return resp, err
+	}
+}
@@ -0,0 +1,92 @@
+// Package metrics implements tonrocket.Metrics with Prometheus collectors. It is a separate
+// module so that users of the core client do not depend on Prometheus.
+package metrics
+
+import (
+	"strconv"
+	"time"
+
+	tonrocket "github.com/croutondefi/tonrocket-go"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Collector is both a tonrocket.Metrics, to pass to tonrocket.WithMetrics, and a
+// prometheus.Collector, to register with a registry.
+type Collector struct {
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+	inFlight prometheus.Gauge
+	retries  *prometheus.CounterVec
+	circuit  prometheus.Gauge
+}
+
+func New() *Collector {
+	return &Collector{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "tonrocket",
+			Name:      "requests_total",
+			Help:      "Requests made to the Rocket API by endpoint and status code.",
+		}, []string{"endpoint", "code"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "tonrocket",
+			Name:      "request_duration_seconds",
+			Help:      "Duration of requests to the Rocket API.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"endpoint"}),
+		inFlight: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: "tonrocket",
+			Name:      "requests_in_flight",
+			Help:      "Requests to the Rocket API currently in progress.",
+		}),
+		retries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "tonrocket",
+			Name:      "retries_total",
+			Help:      "Retried attempts of requests to the Rocket API.",
+		}, []string{"endpoint"}),
+		circuit: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: "tonrocket",
+			Name:      "circuit_breaker_state",
+			Help:      "Circuit breaker state: 0 closed, 1 open, 2 half-open.",
+		}),
+	}
+}
+
+func (c *Collector) RequestStarted(endpoint string) {
+	c.inFlight.Inc()
+}
+
+func (c *Collector) RequestFinished(endpoint string, status int, err error, duration time.Duration) {
+	c.inFlight.Dec()
+
+	code := strconv.Itoa(status)
+	if err != nil {
+		code = "error"
+	}
+
+	c.requests.WithLabelValues(endpoint, code).Inc()
+	c.duration.WithLabelValues(endpoint).Observe(duration.Seconds())
+}
+
+func (c *Collector) RequestRetried(endpoint string) {
+	c.retries.WithLabelValues(endpoint).Inc()
+}
+
+func (c *Collector) CircuitStateChanged(state tonrocket.CircuitState) {
+	c.circuit.Set(float64(state))
+}
+
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	c.requests.Describe(ch)
+	c.duration.Describe(ch)
+	c.inFlight.Describe(ch)
+	c.retries.Describe(ch)
+	c.circuit.Describe(ch)
+}
+
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.requests.Collect(ch)
+	c.duration.Collect(ch)
+	c.inFlight.Collect(ch)
+	c.retries.Collect(ch)
+	c.circuit.Collect(ch)
+}
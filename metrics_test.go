@@ -0,0 +1,54 @@
+package tonrocket
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+type recordingMetrics struct {
+	mu       sync.Mutex
+	finished []string
+	tagged   []string
+}
+
+func (m *recordingMetrics) RequestStarted(string) {}
+
+func (m *recordingMetrics) RequestFinished(endpoint string, _ int, _ error, _ time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.finished = append(m.finished, endpoint)
+}
+
+func (m *recordingMetrics) TaggedRequestFinished(tag, endpoint string, _ int, _ error, _ time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.tagged = append(m.tagged, tag+" "+endpoint)
+}
+
+func (m *recordingMetrics) CircuitStateChanged(CircuitState) {}
+
+func TestTaggedMetrics(t *testing.T) {
+	metrics := &recordingMetrics{}
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		writeData(w, map[string]any{"id": 7})
+	}, WithMetrics(metrics))
+
+	if _, err := client.GetInvoice(WithRequestTag(context.Background(), "order-42"), "7"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.GetInvoice(context.Background(), "7"); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(metrics.finished) != 2 {
+		t.Fatalf("finished = %v, want both requests", metrics.finished)
+	}
+	if len(metrics.tagged) != 1 || metrics.tagged[0] != "order-42 /tg-invoices/:id" {
+		t.Fatalf("tagged = %v, want only the tagged request", metrics.tagged)
+	}
+}
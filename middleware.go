@@ -0,0 +1,78 @@
+package tonrocket
+
+import (
+	"net/http"
+	"time"
+)
+
+type RoundTripFunc func(*http.Request) (*http.Response, error)
+
+// Middleware wraps the execution of a request. It can change the request, short-circuit it
+// or inspect the response.
+type Middleware func(next RoundTripFunc) RoundTripFunc
+
+// buildChain composes the request pipeline. Middlewares added with WithMiddleware run first,
+// outermost first in the order they were added. They are followed by the built-in ones:
+// metrics, the circuit breaker, retries (which also apply the per-attempt timeout) and
+// hedging if enabled, the client-side rate and concurrency limits, rate limit and deprecation
+// header tracking, then auth header injection, then the diagnostic log if enabled, then the
+// HTTP client.
+func (t *tonrocket) buildChain() RoundTripFunc {
+	chain := t.httpClient.Do
+
+	if t.diagnosticLog != nil {
+		chain = t.diagnosticMiddleware(chain)
+	}
+
+	chain = t.authMiddleware(chain)
+	chain = t.rateLimitMiddleware(chain)
+	chain = t.limiterMiddleware(chain)
+
+	if t.hedgeDelay > 0 {
+		chain = t.hedgingMiddleware(chain)
+	}
+
+	if t.retry.maxAttempts > 1 || t.perAttemptTimeout > 0 {
+		chain = t.retryMiddleware(chain)
+	}
+
+	if t.circuitBreaker != nil {
+		chain = t.circuitBreaker.middleware(chain)
+	}
+
+	if t.metrics != nil {
+		chain = t.metricsMiddleware(chain)
+	}
+
+	for i := len(t.middlewares) - 1; i >= 0; i-- {
+		chain = t.middlewares[i](chain)
+	}
+
+	return chain
+}
+
+func (t *tonrocket) authMiddleware(next RoundTripFunc) RoundTripFunc {
+	if t.tokens != nil {
+		return t.tokenAuth(next)
+	}
+
+	return func(req *http.Request) (*http.Response, error) {
+		req.Header.Set(AuthHeader, t.token)
+		return next(req)
+	}
+}
+
+func (t *tonrocket) rateLimitMiddleware(next RoundTripFunc) RoundTripFunc {
+	return func(req *http.Request) (*http.Response, error) {
+		start := time.Now()
+		resp, err := next(req)
+		if err == nil {
+			t.updateRateLimit(resp.Header)
+			t.checkDeprecation(req, resp.Header)
+			if t.clockCorrection {
+				t.updateClockOffset(resp.Header, start)
+			}
+		}
+		return resp, err
+	}
+}
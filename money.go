@@ -0,0 +1,100 @@
+package tonrocket
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+var ErrCurrencyMismatch = errors.New("currency mismatch")
+
+// Money is an amount together with its currency. Arithmetic refuses to combine amounts in
+// different currencies.
+type Money struct {
+	Amount   decimal.Decimal
+	Currency Currency
+}
+
+func NewMoney(amount decimal.Decimal, currency Currency) Money {
+	return Money{Amount: amount, Currency: currency}
+}
+
+func (m Money) check(other Money) error {
+	if m.Currency != other.Currency {
+		return fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, other.Currency)
+	}
+
+	return nil
+}
+
+func (m Money) Add(other Money) (Money, error) {
+	if err := m.check(other); err != nil {
+		return Money{}, err
+	}
+
+	return Money{Amount: m.Amount.Add(other.Amount), Currency: m.Currency}, nil
+}
+
+func (m Money) Sub(other Money) (Money, error) {
+	if err := m.check(other); err != nil {
+		return Money{}, err
+	}
+
+	return Money{Amount: m.Amount.Sub(other.Amount), Currency: m.Currency}, nil
+}
+
+// Cmp compares m to other like decimal.Decimal.Cmp.
+func (m Money) Cmp(other Money) (int, error) {
+	if err := m.check(other); err != nil {
+		return 0, err
+	}
+
+	return m.Amount.Cmp(other.Amount), nil
+}
+
+func (m Money) Mul(factor decimal.Decimal) Money {
+	return Money{Amount: m.Amount.Mul(factor), Currency: m.Currency}
+}
+
+func (m Money) IsZero() bool {
+	return m.Amount.IsZero()
+}
+
+func (m Money) String() string {
+	return FormatAmount(m.Amount, m.Currency)
+}
+
+func (i *Invoice) Money() Money {
+	return NewMoney(i.Amount, i.Currency)
+}
+
+func (t *Transfer) Money() Money {
+	return NewMoney(t.Amount, t.Currency)
+}
+
+func (w *Withdrawal) Money() Money {
+	return NewMoney(w.Amount, w.Currency)
+}
+
+func (b Balance) Money() Money {
+	return NewMoney(b.Balance, b.Currency)
+}
+
+func (r CreateTransferRequest) Money() Money {
+	return NewMoney(r.Amount, r.Currency)
+}
+
+// SetMoney sets Amount and Currency together.
+func (r *CreateTransferRequest) SetMoney(m Money) {
+	r.Amount, r.Currency = m.Amount, m.Currency
+}
+
+func (r CreateWithdrawalRequest) Money() Money {
+	return NewMoney(r.Amount, r.Currency)
+}
+
+// SetMoney sets Amount and Currency together.
+func (r *CreateWithdrawalRequest) SetMoney(m Money) {
+	r.Amount, r.Currency = m.Amount, m.Currency
+}
@@ -0,0 +1,60 @@
+package tonrocket
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/shopspring/decimal"
+)
+
+type ChequeState string
+
+const (
+	ChequeActive    ChequeState = "active"
+	ChequeCompleted ChequeState = "completed"
+	ChequeDraft     ChequeState = "draft"
+)
+
+// Description returns a phrase for showing the state to people, or the raw value if the state
+// is unknown.
+func (s ChequeState) Description() string {
+	switch s {
+	case ChequeActive:
+		return "Active"
+	case ChequeCompleted:
+		return "All activations used"
+	case ChequeDraft:
+		return "Draft, not published"
+	}
+
+	return string(s)
+}
+
+// MultiCheque is a cheque that several users can activate. Rocket reports whether captcha is
+// enabled but no captcha statistics, failed attempts are not counted by the API. Activations
+// are only available as the aggregate Activations count, there is no per-activation history.
+type MultiCheque struct {
+	ID                 int64           `json:"id"`
+	Currency           Currency        `json:"currency"`
+	Total              decimal.Decimal `json:"total"`
+	PerUser            decimal.Decimal `json:"perUser"`
+	Users              int             `json:"users"`
+	Password           string          `json:"password"`
+	Description        string          `json:"description"`
+	SendNotifications  bool            `json:"sendNotifications"`
+	CaptchaEnabled     bool            `json:"captchaEnabled"`
+	RefProgramPercents decimal.Decimal `json:"refProgramPercents"`
+	RefRewardPerUser   decimal.Decimal `json:"refRewardPerUser"`
+	State              ChequeState     `json:"state"`
+	Link               string          `json:"link"`
+	Activations        int             `json:"activations"`
+	RefRewards         int             `json:"refRewards"`
+}
+
+func (t *tonrocket) GetMultiCheque(ctx context.Context, id int64) (*MultiCheque, error) {
+	var resp = &MultiCheque{}
+
+	err := t.getRequest(ctx, t.endpoints.MultiCheques+"/"+strconv.FormatInt(id, 10), nil, resp)
+
+	return resp, err
+}
@@ -0,0 +1,83 @@
+package tonrocket
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// MultiError collects the failures of an operation on several items. Errors is aligned with
+// the items, entries for items that succeeded are nil.
+type MultiError struct {
+	Errors []error
+}
+
+// NewMultiError returns a *MultiError for errs, or nil if none of them is non-nil.
+func NewMultiError(errs []error) error {
+	for _, err := range errs {
+		if err != nil {
+			return &MultiError{Errors: errs}
+		}
+	}
+
+	return nil
+}
+
+// Failed returns the indexes of the items that failed.
+func (e *MultiError) Failed() []int {
+	var failed []int
+	for i, err := range e.Errors {
+		if err != nil {
+			failed = append(failed, i)
+		}
+	}
+
+	return failed
+}
+
+// Unwrap returns the non-nil errors, so errors.Is and errors.As match any of them on Go 1.20
+// and later.
+func (e *MultiError) Unwrap() []error {
+	var errs []error
+	for _, err := range e.Errors {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errs
+}
+
+// Is reports whether any of the errors matches target, for errors.Is before Go 1.20.
+func (e *MultiError) Is(target error) bool {
+	for _, err := range e.Errors {
+		if err != nil && errors.Is(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// As finds the first of the errors that matches target, for errors.As before Go 1.20.
+func (e *MultiError) As(target any) bool {
+	for _, err := range e.Errors {
+		if err != nil && errors.As(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (e *MultiError) Error() string {
+	failed := e.Failed()
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d of %d items failed", len(failed), len(e.Errors))
+	for _, i := range failed {
+		fmt.Fprintf(&b, "; item %d: %v", i, e.Errors[i])
+	}
+
+	return b.String()
+}
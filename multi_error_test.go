@@ -0,0 +1,70 @@
+package tonrocket
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestCreateInvoicesMultiError(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		writeData(w, map[string]any{"id": 1})
+	})
+
+	reqs := []CreateInvoiceRequest{
+		{Amount: 1, Currency: TONCurrency, NumPayments: 1},
+		{Amount: 1, Currency: TONCurrency, NumPayments: 1},
+	}
+
+	invoices, err := client.CreateInvoices(WithValidateOnly(context.Background()), reqs, 2)
+	if len(invoices) != len(reqs) {
+		t.Fatalf("got %d results, want %d", len(invoices), len(reqs))
+	}
+
+	var merr *MultiError
+	if !errors.As(err, &merr) {
+		t.Fatalf("err = %v, want a *MultiError", err)
+	}
+	if got := merr.Failed(); len(got) != 2 {
+		t.Fatalf("failed = %v, want both items", got)
+	}
+	if !errors.Is(err, ErrValidatedLocally) {
+		t.Fatalf("errors.Is(%v, ErrValidatedLocally) = false", err)
+	}
+
+	invoices, err = client.CreateInvoices(context.Background(), reqs, 2)
+	if err != nil {
+		t.Fatalf("CreateInvoices: %v", err)
+	}
+	for i, inv := range invoices {
+		if inv == nil {
+			t.Fatalf("invoice %d is nil", i)
+		}
+	}
+}
+
+func TestMultiErrorIsAs(t *testing.T) {
+	apiErr := &APIError{Message: "rejected"}
+	err := NewMultiError([]error{nil, ErrNoData, apiErr})
+
+	if !errors.Is(err, ErrNoData) {
+		t.Error("errors.Is does not match an item error")
+	}
+	if errors.Is(err, ErrCircuitOpen) {
+		t.Error("errors.Is matches an error that is not an item")
+	}
+
+	var target *APIError
+	if !errors.As(err, &target) || target != apiErr {
+		t.Errorf("errors.As = %v, want the item *APIError", target)
+	}
+
+	if msg := err.Error(); !strings.HasPrefix(msg, "2 of 3 items failed") {
+		t.Errorf("Error() = %q", msg)
+	}
+	if NewMultiError([]error{nil, nil}) != nil {
+		t.Error("NewMultiError of no failures is not nil")
+	}
+}
@@ -0,0 +1,58 @@
+package tonrocket
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+type Network string
+
+const (
+	NetworkTON Network = "TON"
+	NetworkBSC Network = "BSC"
+	NetworkETH Network = "ETH"
+	NetworkBTC Network = "BTC"
+	NetworkTRX Network = "TRX"
+	NetworkSOL Network = "SOL"
+)
+
+var ErrInvalidAddress = errors.New("invalid address")
+
+var (
+	evmAddressRe = regexp.MustCompile(`^0x[0-9a-fA-F]{40}$`)
+	trxAddressRe = regexp.MustCompile(`^T[1-9A-HJ-NP-Za-km-z]{33}$`)
+	btcAddressRe = regexp.MustCompile(`^([13][1-9A-HJ-NP-Za-km-z]{25,34}|bc1[02-9ac-hj-np-z]{39,59})$`)
+	solAddressRe = regexp.MustCompile(`^[1-9A-HJ-NP-Za-km-z]{32,44}$`)
+)
+
+// ValidateAddress performs a format sanity check of address for the network: prefix,
+// length and alphabet. Only TON addresses have their checksum verified, for other networks
+// a well-formed mistyped address can still pass.
+func ValidateAddress(network Network, address string) error {
+	var re *regexp.Regexp
+
+	switch network {
+	case NetworkTON:
+		return ValidateTONAddress(address)
+	case NetworkBSC, NetworkETH:
+		re = evmAddressRe
+	case NetworkBTC:
+		re = btcAddressRe
+	case NetworkTRX:
+		re = trxAddressRe
+	case NetworkSOL:
+		re = solAddressRe
+	case "":
+		return errors.New("network is required")
+	default:
+		return fmt.Errorf("unknown network %q", network)
+	}
+
+	if !re.MatchString(strings.TrimSpace(address)) {
+		return fmt.Errorf("%w: %q is not a %s address", ErrInvalidAddress, address, network)
+	}
+
+	return nil
+}
@@ -0,0 +1,349 @@
+package tonrocket
+
+import (
+	"io"
+	"strings"
+	"time"
+)
+
+type Option func(*tonrocket)
+
+// WithTestnet sends requests to the Rocket testnet API. Rocket has no faucet endpoint, the
+// testnet app balance has to be topped up by depositing testnet coins to it through the
+// testnet bot.
+func WithTestnet() Option {
+	return func(t *tonrocket) {
+		t.testingMode = true
+	}
+}
+
+// WithEndpoints overrides API paths, for deployments that serve them elsewhere. Empty
+// fields keep the DefaultEndpoints path.
+func WithEndpoints(endpoints Endpoints) Option {
+	return func(t *tonrocket) {
+		t.endpoints = endpoints.merge(DefaultEndpoints)
+	}
+}
+
+// WithBalanceCheck makes CreateWithdrawal verify the amount plus the network fee is covered
+// by the app balance before sending the request. It costs an extra AppInfo and
+// WithdrawalFees call per withdrawal.
+func WithBalanceCheck() Option {
+	return func(t *tonrocket) {
+		t.balanceCheck = true
+	}
+}
+
+// WithWithdrawalMinimums makes CreateWithdrawal reject, before sending, amounts below the
+// currency's minimum withdrawal from the currency limits (see CurrencyLimits) and amounts that
+// do not exceed a network fee charged in the same currency. It costs an extra WithdrawalFees
+// call per withdrawal.
+func WithWithdrawalMinimums() Option {
+	return func(t *tonrocket) {
+		t.withdrawalMinimums = true
+	}
+}
+
+// WithPartialData makes methods decode the data returned alongside a failed response into
+// their result, so both the result and the *APIError are returned. By default the result is
+// left empty when the request fails.
+func WithPartialData() Option {
+	return func(t *tonrocket) {
+		t.partialData = true
+	}
+}
+
+// WithoutAddressValidation disables the ValidateAddress check CreateWithdrawal runs before
+// sending, for addresses the check does not recognise.
+func WithoutAddressValidation() Option {
+	return func(t *tonrocket) {
+		t.addressValidation = false
+	}
+}
+
+// WithDefaultExpiry sets ExpiredIn, in whole seconds, on invoices created without one.
+func WithDefaultExpiry(d time.Duration) Option {
+	return func(t *tonrocket) {
+		t.defaultExpiry = d
+	}
+}
+
+// WithDefaultCurrency sets Currency on invoices created without one.
+func WithDefaultCurrency(currency Currency) Option {
+	return func(t *tonrocket) {
+		t.defaultCurrency = currency
+	}
+}
+
+// WithDefaultCallbackURL sets CallbackURL on invoices created without one.
+func WithDefaultCallbackURL(callbackURL string) Option {
+	return func(t *tonrocket) {
+		t.defaultCallbackURL = callbackURL
+	}
+}
+
+// WithDisplayLocation sets the location DisplayTime and FormatTime convert to. Decoded
+// timestamps are not affected.
+func WithDisplayLocation(loc *time.Location) Option {
+	return func(t *tonrocket) {
+		if loc != nil {
+			t.displayLocation = loc
+		}
+	}
+}
+
+func WithLogger(logger Logger) Option {
+	return func(t *tonrocket) {
+		t.logger = logger
+	}
+}
+
+// WithSchemaWarnings logs, through the logger, response fields the client does not know
+// about and known fields the response lacks. Only top level fields of the data object are
+// compared. Decoding is not affected.
+func WithSchemaWarnings(enabled bool) Option {
+	return func(t *tonrocket) {
+		t.schemaWarnings = enabled
+	}
+}
+
+// WithPayloadIdempotency makes CreateInvoice store the context's idempotency key (see
+// WithIdempotencyKey) in the payload and, before creating, look through the lookback most
+// recent invoices for one carrying the same key, returning it instead of creating a new one.
+// This is best effort: two concurrent calls with the same key can both miss the other and
+// create duplicates.
+func WithPayloadIdempotency(lookback int) Option {
+	return func(t *tonrocket) {
+		t.payloadIdempotency = lookback
+	}
+}
+
+// WithSuccessPredicate replaces the check of the success field in the response envelope,
+// for gateways that reshape responses. The predicate runs before data is decoded, and a
+// failure it reports is returned as *APIError.
+func WithSuccessPredicate(predicate SuccessPredicate) Option {
+	return func(t *tonrocket) {
+		t.successPredicate = predicate
+	}
+}
+
+// WithMiddleware adds a middleware to the request pipeline. Middlewares run in the order
+// they are added, the first one outermost, and all of them run before the built-in rate
+// limit tracking and auth header injection. To run a middleware before the others, pass it
+// first.
+func WithMiddleware(middleware Middleware) Option {
+	return func(t *tonrocket) {
+		t.middlewares = append(t.middlewares, middleware)
+	}
+}
+
+// WithCircuitBreaker makes the client fail fast with ErrCircuitOpen after failureThreshold
+// consecutive failed requests (transport errors or 5xx responses). After cooldown one probe
+// request is let through, and its outcome closes or reopens the circuit.
+func WithCircuitBreaker(failureThreshold int, cooldown time.Duration) Option {
+	return func(t *tonrocket) {
+		if failureThreshold < 1 {
+			failureThreshold = 1
+		}
+		t.circuitBreaker = &circuitBreaker{
+			threshold: failureThreshold,
+			cooldown:  cooldown,
+		}
+	}
+}
+
+// WithMetrics reports every request, and circuit breaker state changes, to m. The metrics
+// subpackage provides a Prometheus implementation.
+func WithMetrics(m Metrics) Option {
+	return func(t *tonrocket) {
+		t.metrics = m
+	}
+}
+
+// WithHedging sends a second copy of a request that has not answered within delay and uses
+// whichever answers first, cancelling the other. Only safe requests are hedged: GETs, and
+// transfers and withdrawals, which Rocket deduplicates by transferId and withdrawalId.
+// Invoice creation is never hedged.
+func WithHedging(delay time.Duration) Option {
+	return func(t *tonrocket) {
+		t.hedgeDelay = delay
+	}
+}
+
+// WithResponseHook calls hook after every request, successful or not, with the raw
+// response body. It runs synchronously before the method returns.
+func WithResponseHook(hook func(ResponseInfo)) Option {
+	return func(t *tonrocket) {
+		t.responseHook = hook
+	}
+}
+
+// WithRetry retries idempotent requests (GETs, transfers and withdrawals) up to maxAttempts
+// attempts in total when they fail with a transport error, 429 or 5xx, waiting backoff
+// before the first retry and doubling the wait after each one.
+func WithRetry(maxAttempts int, backoff time.Duration) Option {
+	return func(t *tonrocket) {
+		t.retry = retryConfig{
+			maxAttempts: maxAttempts,
+			backoff:     backoff,
+		}
+	}
+}
+
+// WithPerAttemptTimeout bounds every attempt of a request by d, so a hung attempt leaves
+// time for retries. The context passed to the method still bounds all attempts together.
+func WithPerAttemptTimeout(d time.Duration) Option {
+	return func(t *tonrocket) {
+		t.perAttemptTimeout = d
+	}
+}
+
+// WithLenientDecoding keeps a response whose strict decoding failed by decoding it field by
+// field: fields that fail are left at their zero value and reported in ResponseInfo.Warnings.
+// The id, amount and status fields are always decoded strictly.
+func WithLenientDecoding() Option {
+	return func(t *tonrocket) {
+		t.lenientDecoding = true
+	}
+}
+
+// WithMaxResponseBytes limits how much of a response body is read. Larger responses fail with
+// ErrResponseTooLarge instead of being buffered. A value of zero or less removes the limit.
+func WithMaxResponseBytes(n int64) Option {
+	return func(t *tonrocket) {
+		t.maxResponseBytes = n
+	}
+}
+
+// WithRequestCompression is a no-op. Rocket does not accept compressed request bodies, so
+// bodies are always sent uncompressed whatever minBytes is.
+func WithRequestCompression(minBytes int) Option {
+	return func(*tonrocket) {}
+}
+
+// WithTokenProvider takes the API token from provider instead of the fixed one passed to
+// NewTonrocket. Within refreshBefore of the token's expiry it is refreshed in the background
+// while requests keep using it, an expired token is refreshed before the request is sent.
+// A request rejected with 401 refreshes the token and is retried once with the new one.
+// Each provider call is bounded by TokenRefreshTimeout, and after a failed one the provider is
+// not called again for TokenRefreshBackoff.
+func WithTokenProvider(provider TokenProvider, refreshBefore time.Duration) Option {
+	return func(t *tonrocket) {
+		t.tokens = &tokenSource{
+			provider:      provider,
+			refreshBefore: refreshBefore,
+			timeout:       TokenRefreshTimeout,
+			backoff:       TokenRefreshBackoff,
+		}
+	}
+}
+
+// WithPriceTable sets the prices used by CreateInvoiceForSKU.
+func WithPriceTable(table PriceTable) Option {
+	return func(t *tonrocket) {
+		t.priceTable = table
+	}
+}
+
+// WithCurrencyAliases maps alternative currency names to the values sent to the API, e.g.
+// DefaultCurrencyAliases maps "TON" to "TONCOIN". Alias keys are matched regardless of case.
+// The mapping applies to every currency the client accepts, including CurrencyLimits and the
+// price lookup of CreateInvoiceForSKU.
+func WithCurrencyAliases(aliases map[Currency]Currency) Option {
+	return func(t *tonrocket) {
+		t.currencyAliases = make(map[Currency]Currency, len(aliases))
+		for alias, canonical := range aliases {
+			t.currencyAliases[Currency(strings.ToUpper(strings.TrimSpace(string(alias))))] = canonical
+		}
+	}
+}
+
+// WithPollJitter sets the fraction WaitForPayment and WatchBalances randomly vary their poll
+// interval by, DefaultPollJitter unless set. rnd returns values in [0, 1) and defaults to
+// math/rand. A fraction of zero disables jitter, for deterministic tests.
+func WithPollJitter(fraction float64, rnd func() float64) Option {
+	return func(t *tonrocket) {
+		t.pollJitter = newPollJitter(fraction, rnd)
+	}
+}
+
+// WithFeeMathPrecision sets the number of decimal places NetAmount and GrossAmount keep when
+// dividing by the fee percentage. By default it is the currency decimals plus 8. The final
+// result is always rounded to the currency decimals.
+func WithFeeMathPrecision(places int32) Option {
+	return func(t *tonrocket) {
+		t.feeMathPrecision = places
+	}
+}
+
+// WithDiagnosticLog writes every request attempt to w as a JSON line with the method, path,
+// headers, request body, status and response body, for attaching to a bug report. The auth
+// header and the password, hiddenMessage, token and secret fields are redacted. Writes to w
+// are serialized.
+func WithDiagnosticLog(w io.Writer) Option {
+	return func(t *tonrocket) {
+		t.diagnosticLog = &diagnosticLog{w: w}
+	}
+}
+
+// WithPrecisionWarnings checks decoded amounts against the decimals of their currency, as
+// listed by Currency.Decimals, and reports amounts with more fractional digits in
+// ResponseInfo.Warnings. Currencies without known decimals are not checked.
+func WithPrecisionWarnings() Option {
+	return func(t *tonrocket) {
+		t.precisionWarnings = true
+	}
+}
+
+// WithIdempotencyConflictDetection returns ErrIdempotencyConflict when an idempotency key is
+// reused with a different invoice request, or a transfer id with a different transfer.
+// Without it, the default, a reused key returns the original invoice and a reused transfer id
+// is sent as is. Invoice requests are compared through a hash stored in the payload next to
+// the key, transfers through hashes kept in memory for the last 10000 transfer ids.
+func WithIdempotencyConflictDetection() Option {
+	return func(t *tonrocket) {
+		t.idempotencyConflicts = true
+	}
+}
+
+// WithDeprecationHandler calls handler the first time a response for an endpoint carries a
+// Deprecation or Sunset header. sunset is the time from the Sunset header, zero if there is
+// none. Deprecations are also logged once per endpoint when a logger is set.
+func WithDeprecationHandler(handler func(endpoint string, sunset time.Time)) Option {
+	return func(t *tonrocket) {
+		t.deprecationHandler = handler
+	}
+}
+
+// WithRateLimit limits requests on the client side, see SetRateLimit.
+func WithRateLimit(rps float64, burst int) Option {
+	return func(t *tonrocket) {
+		t.limiter.setRate(rps, burst)
+	}
+}
+
+// WithMaxConcurrency limits the number of requests in flight, see SetMaxConcurrency.
+func WithMaxConcurrency(n int) Option {
+	return func(t *tonrocket) {
+		t.limiter.setMaxConcurrency(n)
+	}
+}
+
+// WithAllowEmptyData accepts a successful response without data and leaves the result at its
+// zero value, instead of returning ErrNoData.
+func WithAllowEmptyData() Option {
+	return func(t *tonrocket) {
+		t.allowEmptyData = true
+	}
+}
+
+// WithClockOffsetCorrection makes Now, IsExpired and TimeUntilExpiry use the server clock
+// instead of the local one. The offset is measured from the Date header of every API
+// response, with one second resolution, and from ClockOffset calls. Until the first
+// response the local clock is used as is. Without this option, the default, no correction
+// is applied.
+func WithClockOffsetCorrection(enabled bool) Option {
+	return func(t *tonrocket) {
+		t.clockCorrection = enabled
+	}
+}
@@ -0,0 +1,311 @@
+package tonrocket
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+type OutboxEntry struct {
+	ID       string               `json:"id"`
+	Request  CreateInvoiceRequest `json:"request"`
+	Created  time.Time            `json:"created"`
+	Attempts int                  `json:"attempts"`
+}
+
+// OutboxStore persists queued entries. List must return them in the order they were put.
+type OutboxStore interface {
+	Put(entry OutboxEntry) error
+	List() ([]OutboxEntry, error)
+	Delete(id string) error
+}
+
+// DefaultOutboxMaxAttempts is how many times an entry is sent before it is given up on,
+// unless WithOutboxMaxAttempts is set.
+const DefaultOutboxMaxAttempts = 10
+
+// OutboxGaveUpError is reported to the outbox callback for an entry that failed on every one
+// of its attempts. Err is the error of the last one.
+type OutboxGaveUpError struct {
+	Attempts int
+	Err      error
+}
+
+func (e *OutboxGaveUpError) Error() string {
+	return fmt.Sprintf("outbox entry given up after %d attempts: %v", e.Attempts, e.Err)
+}
+
+func (e *OutboxGaveUpError) Unwrap() error {
+	return e.Err
+}
+
+type OutboxOption func(*Outbox)
+
+// WithOutboxMaxAttempts sets how many times an entry is sent before it is given up on. Values
+// below one keep DefaultOutboxMaxAttempts.
+func WithOutboxMaxAttempts(n int) OutboxOption {
+	return func(o *Outbox) {
+		if n > 0 {
+			o.maxAttempts = n
+		}
+	}
+}
+
+// Outbox queues invoice creations while the API is unreachable and replays them in order
+// once it is back. Delivery is at least once: an entry is removed only after its result has
+// been handed to the callback, so a crash in between replays it. Each entry is sent with its
+// ID as idempotency key (see WithIdempotencyKey), so a replay of an invoice that was created
+// returns it instead of creating another.
+type Outbox struct {
+	client      Tonrocket
+	store       OutboxStore
+	onResult    func(OutboxEntry, *Invoice, error)
+	maxAttempts int
+}
+
+// NewOutbox returns an outbox draining store through client. onResult is called once per
+// entry with the created invoice, or with the error if the API rejected the request or the
+// entry was given up on. It panics if client was created without WithPayloadIdempotency,
+// without which replays can create duplicate invoices.
+func NewOutbox(client Tonrocket, store OutboxStore, onResult func(OutboxEntry, *Invoice, error), opts ...OutboxOption) *Outbox {
+	if t, ok := client.(*tonrocket); ok && t.payloadIdempotency <= 0 {
+		panic("tonrocket: outbox client needs WithPayloadIdempotency to replay without duplicates")
+	}
+
+	o := &Outbox{
+		client:      client,
+		store:       store,
+		onResult:    onResult,
+		maxAttempts: DefaultOutboxMaxAttempts,
+	}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+func (o *Outbox) EnqueueCreateInvoice(req CreateInvoiceRequest) (string, error) {
+	id, err := newOutboxID()
+	if err != nil {
+		return "", err
+	}
+
+	return id, o.store.Put(OutboxEntry{
+		ID:      id,
+		Request: req,
+		Created: time.Now().UTC(),
+	})
+}
+
+// Run drains the outbox every interval until ctx is done. Draining stops at the first entry
+// that fails for a reason other than an API rejection, so later entries are never sent
+// before earlier ones, unless that entry is given up on.
+func (o *Outbox) Run(ctx context.Context, interval time.Duration) error {
+	if interval < MinPollInterval {
+		interval = MinPollInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := o.Drain(ctx); err != nil && ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Drain sends queued entries in order and returns the error that stopped it, if any.
+func (o *Outbox) Drain(ctx context.Context) error {
+	entries, err := o.store.List()
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		inv, err := o.client.CreateInvoice(WithIdempotencyKey(ctx, entry.ID), entry.Request)
+		if err != nil && !isPermanent(err) {
+			if ctx.Err() != nil {
+				return err
+			}
+
+			entry.Attempts++
+			if entry.Attempts < o.maxAttempts {
+				_ = o.store.Put(entry)
+				return err
+			}
+			err = &OutboxGaveUpError{Attempts: entry.Attempts, Err: err}
+		}
+
+		if o.onResult != nil {
+			o.onResult(entry, inv, err)
+		}
+
+		if err := o.store.Delete(entry.ID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// isPermanent reports whether retrying the request cannot change the outcome. An API error
+// is only permanent if its status is not one of the temporary 429 and 5xx.
+func isPermanent(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return !apiErr.Temporary()
+	}
+
+	var validationErr *ValidationError
+
+	return errors.As(err, &validationErr) || errors.Is(err, ErrPayloadTooLong) ||
+		errors.Is(err, ErrValidatedLocally)
+}
+
+func newOutboxID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+// MemoryOutboxStore keeps entries in memory. It does not survive a restart.
+type MemoryOutboxStore struct {
+	mu      sync.Mutex
+	entries []OutboxEntry
+}
+
+func (s *MemoryOutboxStore) Put(entry OutboxEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries = putEntry(s.entries, entry)
+
+	return nil
+}
+
+func (s *MemoryOutboxStore) List() ([]OutboxEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]OutboxEntry(nil), s.entries...), nil
+}
+
+func (s *MemoryOutboxStore) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries = deleteEntry(s.entries, id)
+
+	return nil
+}
+
+// FileOutboxStore keeps entries in a JSON file, rewritten atomically on every change.
+type FileOutboxStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+func NewFileOutboxStore(path string) *FileOutboxStore {
+	return &FileOutboxStore{path: path}
+}
+
+func (s *FileOutboxStore) Put(entry OutboxEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := s.read()
+	if err != nil {
+		return err
+	}
+
+	return s.write(putEntry(entries, entry))
+}
+
+func (s *FileOutboxStore) List() ([]OutboxEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.read()
+}
+
+func (s *FileOutboxStore) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := s.read()
+	if err != nil {
+		return err
+	}
+
+	return s.write(deleteEntry(entries, id))
+}
+
+func (s *FileOutboxStore) read() ([]OutboxEntry, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []OutboxEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func (s *FileOutboxStore) write(entries []OutboxEntry) error {
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, s.path)
+}
+
+func putEntry(entries []OutboxEntry, entry OutboxEntry) []OutboxEntry {
+	for i := range entries {
+		if entries[i].ID == entry.ID {
+			entries[i] = entry
+			return entries
+		}
+	}
+
+	return append(entries, entry)
+}
+
+func deleteEntry(entries []OutboxEntry, id string) []OutboxEntry {
+	for i := range entries {
+		if entries[i].ID == id {
+			return append(entries[:i], entries[i+1:]...)
+		}
+	}
+
+	return entries
+}
@@ -0,0 +1,166 @@
+package tonrocket
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+)
+
+// statusLost makes invoiceServer create the invoice and then answer 500, as when the
+// response is lost.
+const statusLost = -1
+
+// invoiceServer creates invoices on POST, answering each with the next of statuses (200 once
+// they run out), and lists the created invoices on GET.
+type invoiceServer struct {
+	mu       sync.Mutex
+	statuses []int
+	posts    int
+	invoices []map[string]any
+}
+
+func (s *invoiceServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r.Method == http.MethodGet {
+		writeData(w, map[string]any{"total": len(s.invoices), "results": s.invoices})
+		return
+	}
+
+	var req CreateInvoiceRequest
+	_ = json.NewDecoder(r.Body).Decode(&req)
+
+	status := http.StatusOK
+	if s.posts < len(s.statuses) {
+		status = s.statuses[s.posts]
+	}
+	s.posts++
+
+	if status != http.StatusOK && status != statusLost {
+		writeError(w, status, "failed")
+		return
+	}
+
+	inv := map[string]any{"id": len(s.invoices) + 1, "description": req.Description, "payload": req.Payload}
+	s.invoices = append([]map[string]any{inv}, s.invoices...)
+
+	if status == statusLost {
+		writeError(w, http.StatusInternalServerError, "failed")
+		return
+	}
+	writeData(w, inv)
+}
+
+type outboxResult struct {
+	description string
+	invoice     *Invoice
+	err         error
+}
+
+func TestOutboxDrain(t *testing.T) {
+	tests := []struct {
+		name        string
+		statuses    []int
+		drains      int
+		wantResults []string
+		wantErrs    []bool
+		wantCreated int
+	}{
+		{"replays in order", nil, 1, []string{"a", "b", "c"}, []bool{false, false, false}, 3},
+		{"retries a 5xx on the next drain", []int{http.StatusOK, http.StatusInternalServerError}, 2,
+			[]string{"a", "b", "c"}, []bool{false, false, false}, 3},
+		{"retries a 429", []int{http.StatusTooManyRequests}, 2, []string{"a", "b", "c"}, []bool{false, false, false}, 3},
+		{"drops a 4xx rejection", []int{http.StatusOK, http.StatusBadRequest}, 1,
+			[]string{"a", "b", "c"}, []bool{false, true, false}, 2},
+		{"idempotency key prevents a duplicate", []int{statusLost}, 2,
+			[]string{"a", "b", "c"}, []bool{false, false, false}, 3},
+		{"gives up after max attempts", []int{500, 500, 500}, 3,
+			[]string{"a", "b", "c"}, []bool{true, false, false}, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := &invoiceServer{statuses: tt.statuses}
+			client := newTestClient(t, srv.ServeHTTP, WithPayloadIdempotency(100))
+
+			var results []outboxResult
+			outbox := NewOutbox(client, &MemoryOutboxStore{}, func(entry OutboxEntry, inv *Invoice, err error) {
+				results = append(results, outboxResult{entry.Request.Description, inv, err})
+			}, WithOutboxMaxAttempts(3))
+
+			for _, description := range []string{"a", "b", "c"} {
+				req := CreateInvoiceRequest{Amount: 1, NumPayments: 1, Currency: TONCurrency, Description: description}
+				if _, err := outbox.EnqueueCreateInvoice(req); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			for i := 0; i < tt.drains; i++ {
+				_ = outbox.Drain(context.Background())
+			}
+
+			if len(results) != len(tt.wantResults) {
+				t.Fatalf("got %d results, want %d", len(results), len(tt.wantResults))
+			}
+			for i, res := range results {
+				if res.description != tt.wantResults[i] || (res.err != nil) != tt.wantErrs[i] {
+					t.Fatalf("result %d = %s (err %v), want %s (err %v)", i, res.description, res.err, tt.wantResults[i], tt.wantErrs[i])
+				}
+				if res.err == nil && (res.invoice == nil || res.invoice.Description != res.description) {
+					t.Fatalf("result %d has invoice %+v", i, res.invoice)
+				}
+			}
+			if len(srv.invoices) != tt.wantCreated {
+				t.Fatalf("server created %d invoices, want %d", len(srv.invoices), tt.wantCreated)
+			}
+
+			if remaining, _ := outbox.store.List(); len(remaining) != 0 {
+				t.Fatalf("%d entries left in the store", len(remaining))
+			}
+		})
+	}
+}
+
+func TestOutboxErrors(t *testing.T) {
+	var apiErr *APIError
+	var gaveUp *OutboxGaveUpError
+
+	srv := &invoiceServer{statuses: []int{http.StatusBadRequest, 500, 500}}
+	client := newTestClient(t, srv.ServeHTTP, WithPayloadIdempotency(100))
+
+	var errs []error
+	outbox := NewOutbox(client, &MemoryOutboxStore{}, func(_ OutboxEntry, _ *Invoice, err error) {
+		errs = append(errs, err)
+	}, WithOutboxMaxAttempts(2))
+
+	for i := 0; i < 2; i++ {
+		_, _ = outbox.EnqueueCreateInvoice(CreateInvoiceRequest{Amount: 1, NumPayments: 1, Currency: TONCurrency})
+	}
+
+	_ = outbox.Drain(context.Background())
+	_ = outbox.Drain(context.Background())
+
+	if len(errs) != 2 {
+		t.Fatalf("got %d results, want 2", len(errs))
+	}
+	if !errors.As(errs[0], &apiErr) || apiErr.StatusCode != http.StatusBadRequest || apiErr.Temporary() {
+		t.Fatalf("first error = %v, want a permanent 400 *APIError", errs[0])
+	}
+	if !errors.As(errs[1], &gaveUp) || gaveUp.Attempts != 2 || !errors.As(errs[1], &apiErr) || !apiErr.Temporary() {
+		t.Fatalf("second error = %v, want *OutboxGaveUpError wrapping a 500", errs[1])
+	}
+}
+
+func TestNewOutboxRequiresPayloadIdempotency(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("NewOutbox accepted a client without WithPayloadIdempotency")
+		}
+	}()
+
+	NewOutbox(NewTonrocket("token"), &MemoryOutboxStore{}, nil)
+}
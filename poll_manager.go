@@ -0,0 +1,162 @@
+package tonrocket
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const maxPollBackoff = 8
+
+type InvoiceStatusEvent struct {
+	Invoice  *Invoice
+	Previous InvoiceStatus
+}
+
+// PollManager polls many invoices on one shared schedule and emits an event when an
+// invoice status changes. Invoices that reach a final status are unregistered automatically.
+type PollManager struct {
+	client      Tonrocket
+	interval    time.Duration
+	concurrency int
+	jitter      pollJitter
+
+	mu     sync.Mutex
+	ids    map[string]InvoiceStatus
+	events chan InvoiceStatusEvent
+}
+
+func NewPollManager(client Tonrocket, interval time.Duration, concurrency int) *PollManager {
+	if interval < MinPollInterval {
+		interval = MinPollInterval
+	}
+
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	return &PollManager{
+		client:      client,
+		interval:    interval,
+		concurrency: concurrency,
+		jitter:      defaultPollJitter,
+		ids:         make(map[string]InvoiceStatus),
+		events:      make(chan InvoiceStatusEvent, concurrency),
+	}
+}
+
+// Register starts tracking an active invoice. Registering an id twice has no effect.
+func (m *PollManager) Register(id string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.ids[id]; !ok {
+		m.ids[id] = InvoiceActive
+	}
+}
+
+func (m *PollManager) Unregister(id string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.ids, id)
+}
+
+// SetJitter sets the fraction the poll interval is randomly varied by, DefaultPollJitter
+// unless set. See WithPollJitter. It must be called before Run.
+func (m *PollManager) SetJitter(fraction float64, rnd func() float64) {
+	m.jitter = newPollJitter(fraction, rnd)
+}
+
+// Events returns the channel status changes are sent to. It is closed when Run returns.
+func (m *PollManager) Events() <-chan InvoiceStatusEvent {
+	return m.events
+}
+
+// Run polls registered invoices until ctx is done. When polls fail the interval is doubled,
+// up to 8 times the configured one, and when the rate limit is exhausted polling waits
+// for it to reset.
+func (m *PollManager) Run(ctx context.Context) error {
+	defer close(m.events)
+
+	backoff := 1
+	for {
+		wait := m.jitter.apply(m.interval * time.Duration(backoff))
+		if rl := m.client.RateLimitStatus(); rl.Remaining == 0 && time.Until(rl.Reset) > wait {
+			wait = time.Until(rl.Reset)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+
+		if m.poll(ctx) {
+			backoff = 1
+		} else if backoff < maxPollBackoff {
+			backoff *= 2
+		}
+	}
+}
+
+func (m *PollManager) poll(ctx context.Context) bool {
+	m.mu.Lock()
+	ids := make([]string, 0, len(m.ids))
+	for id := range m.ids {
+		ids = append(ids, id)
+	}
+	m.mu.Unlock()
+
+	var (
+		wg     sync.WaitGroup
+		sem    = make(chan struct{}, m.concurrency)
+		failed bool
+		failMu sync.Mutex
+	)
+
+	for _, id := range ids {
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func(id string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			inv, err := m.client.GetInvoice(ctx, id)
+			if err != nil {
+				failMu.Lock()
+				failed = true
+				failMu.Unlock()
+				return
+			}
+
+			m.update(ctx, id, inv)
+		}(id)
+	}
+
+	wg.Wait()
+
+	return !failed
+}
+
+func (m *PollManager) update(ctx context.Context, id string, inv *Invoice) {
+	m.mu.Lock()
+	previous, ok := m.ids[id]
+	if !ok || previous == inv.Status {
+		m.mu.Unlock()
+		return
+	}
+
+	if inv.Status == InvoiceActive {
+		m.ids[id] = inv.Status
+	} else {
+		delete(m.ids, id)
+	}
+	m.mu.Unlock()
+
+	select {
+	case m.events <- InvoiceStatusEvent{Invoice: inv, Previous: previous}:
+	case <-ctx.Done():
+	}
+}
@@ -0,0 +1,49 @@
+package tonrocket
+
+import (
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// checkPrecision adds a warning for every decoded amount with more fractional digits than its
+// currency uses. Only currencies with known decimals, see Currency.Decimals, are checked.
+func checkPrecision(target any, warnings *[]string) {
+	check := func(field string, currency Currency, amount decimal.Decimal) {
+		decimals, ok := currencyDecimals[currency]
+		if !ok || -amount.Exponent() <= decimals {
+			return
+		}
+		if amount.Equal(amount.Round(decimals)) {
+			return
+		}
+
+		*warnings = append(*warnings, fmt.Sprintf("%s %s has more than %d decimals for %s", field, amount, decimals, currency))
+	}
+
+	checkInvoice := func(inv *Invoice) {
+		if inv != nil {
+			check("invoice amount", inv.Currency, inv.Amount)
+		}
+	}
+
+	switch v := target.(type) {
+	case *Invoice:
+		checkInvoice(v)
+	case *page[*Invoice]:
+		for _, inv := range v.Results {
+			checkInvoice(inv)
+		}
+	case *Transfer:
+		check("transfer amount", v.Currency, v.Amount)
+	case *Withdrawal:
+		check("withdrawal amount", v.Currency, v.Amount)
+	case *MultiCheque:
+		check("cheque total", v.Currency, v.Total)
+		check("cheque perUser", v.Currency, v.PerUser)
+	case *AppInfo:
+		for _, b := range v.Balances {
+			check("balance", b.Currency, b.Balance)
+		}
+	}
+}
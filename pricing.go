@@ -0,0 +1,53 @@
+package tonrocket
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+var ErrNotPriced = errors.New("no price for sku")
+
+// PriceTable looks up the price of a product in a currency.
+type PriceTable interface {
+	Price(sku string, currency Currency) (decimal.Decimal, bool)
+}
+
+// MapPriceTable is a PriceTable keyed by sku, then currency.
+type MapPriceTable map[string]map[Currency]decimal.Decimal
+
+func (m MapPriceTable) Price(sku string, currency Currency) (decimal.Decimal, bool) {
+	price, ok := m[sku][currency]
+	return price, ok
+}
+
+// CreateInvoiceForSKU creates a single payment invoice for the price of sku in currency, taken
+// from the table set with WithPriceTable. The client defaults apply as in CreateInvoice.
+// Invoice amounts are sent as float64, a price with more significant digits than survive a
+// float64 round-trip, about 15, is rejected rather than rounded.
+func (t *tonrocket) CreateInvoiceForSKU(ctx context.Context, sku string, currency Currency) (*Invoice, error) {
+	if t.priceTable == nil {
+		return nil, errors.New("no price table configured")
+	}
+
+	currency = t.normalizeCurrency(currency)
+
+	price, ok := t.priceTable.Price(sku, currency)
+	if !ok {
+		return nil, fmt.Errorf("%w %q in %s", ErrNotPriced, sku, currency)
+	}
+
+	amount := price.InexactFloat64()
+	if !decimal.NewFromFloat(amount).Equal(price) {
+		return nil, fmt.Errorf("price %s of %q in %s does not survive a float64 round-trip as an invoice amount", price, sku, currency)
+	}
+
+	req, err := NewInvoice(amount, currency).Build()
+	if err != nil {
+		return nil, err
+	}
+
+	return t.CreateInvoice(ctx, req)
+}
@@ -0,0 +1,59 @@
+package tonrocket
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestCreateInvoiceForSKU(t *testing.T) {
+	table := MapPriceTable{
+		"seat":    {TONCurrency: decimal.RequireFromString("12.5")},
+		"precise": {TONCurrency: decimal.RequireFromString("0.12345678901234567890")},
+	}
+
+	tests := []struct {
+		name       string
+		sku        string
+		wantAmount float64
+		wantErr    bool
+		notPriced  bool
+	}{
+		{"exact price", "seat", 12.5, false, false},
+		{"price float64 cannot hold", "precise", 0, true, false},
+		{"unknown sku", "missing", 0, true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body CreateInvoiceRequest
+			sent := false
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				sent = true
+				_ = json.NewDecoder(r.Body).Decode(&body)
+				writeData(w, map[string]any{"id": 1})
+			}, WithPriceTable(table))
+
+			_, err := client.CreateInvoiceForSKU(context.Background(), tt.sku, TONCurrency)
+			if tt.wantErr {
+				if err == nil || sent {
+					t.Fatalf("err = %v, sent = %v, want an error before sending", err, sent)
+				}
+				if got := errors.Is(err, ErrNotPriced); got != tt.notPriced {
+					t.Fatalf("errors.Is(%v, ErrNotPriced) = %v", err, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CreateInvoiceForSKU: %v", err)
+			}
+			if body.Amount != tt.wantAmount {
+				t.Fatalf("amount = %v, want %v", body.Amount, tt.wantAmount)
+			}
+		})
+	}
+}
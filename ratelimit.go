@@ -0,0 +1,53 @@
+package tonrocket
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	rateLimitRemainingHeader = "X-RateLimit-Remaining"
+	rateLimitResetHeader     = "X-RateLimit-Reset"
+)
+
+// RateLimitStatus is the rate limit state reported by the latest response carrying
+// X-RateLimit-* headers. Updated is zero if no such response has been seen yet.
+type RateLimitStatus struct {
+	Remaining int
+	Reset     time.Time
+	Updated   time.Time
+}
+
+func (t *tonrocket) RateLimitStatus() RateLimitStatus {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.rateLimit
+}
+
+func (t *tonrocket) updateRateLimit(header http.Header) {
+	remaining, err := strconv.Atoi(header.Get(rateLimitRemainingHeader))
+	if err != nil {
+		return
+	}
+
+	now := time.Now()
+	status := RateLimitStatus{
+		Remaining: remaining,
+		Updated:   now,
+	}
+
+	// Reset is either a unix timestamp or a number of seconds from now.
+	if reset, err := strconv.ParseInt(header.Get(rateLimitResetHeader), 10, 64); err == nil {
+		if reset > 1e9 {
+			status.Reset = time.Unix(reset, 0)
+		} else {
+			status.Reset = now.Add(time.Duration(reset) * time.Second)
+		}
+	}
+
+	t.mu.Lock()
+	t.rateLimit = status
+	t.mu.Unlock()
+}
@@ -0,0 +1,37 @@
+package tonrocket
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// VerifyReachable sends a HEAD request to the invoice link and returns an error unless it
+// answers with a 2xx or 3xx status. client defaults to http.DefaultClient.
+func (i *Invoice) VerifyReachable(ctx context.Context, client *http.Client) error {
+	if i.Link == "" {
+		return errors.New("invoice has no link")
+	}
+
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, i.Link, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("invoice link is not reachable: %w", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("invoice link returned %s", resp.Status)
+	}
+
+	return nil
+}
@@ -0,0 +1,44 @@
+package tonrocket
+
+import (
+	"net/http"
+	"time"
+)
+
+// ResponseInfo describes a finished request. Body is the response exactly as received and
+// must not be modified. It is nil if no response arrived. Warnings lists problems that did
+// not fail the request, such as fields skipped by lenient decoding.
+type ResponseInfo struct {
+	Method     string
+	Endpoint   string
+	Tag        string
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+	Err        error
+	Warnings   []string
+	Time       time.Time
+}
+
+func (t *tonrocket) fireResponseHook(req *http.Request, resp *http.Response, body []byte, warnings []string, err error) {
+	if t.responseHook == nil {
+		return
+	}
+
+	info := ResponseInfo{
+		Method:   req.Method,
+		Endpoint: endpointLabel(req.URL.Path),
+		Tag:      RequestTag(req.Context()),
+		Body:     body,
+		Err:      err,
+		Warnings: warnings,
+		Time:     time.Now(),
+	}
+
+	if resp != nil {
+		info.StatusCode = resp.StatusCode
+		info.Header = resp.Header
+	}
+
+	t.responseHook(info)
+}
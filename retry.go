@@ -0,0 +1,116 @@
+package tonrocket
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+type retryConfig struct {
+	maxAttempts int
+	backoff     time.Duration
+}
+
+// RetryMetrics can be implemented by a Metrics to also count retried attempts.
+type RetryMetrics interface {
+	RequestRetried(endpoint string)
+}
+
+type retryBudgetKey struct{}
+
+// WithRetryBudget returns a context that allows at most n retries in total across all requests
+// made with it or with contexts derived from it. Each retry takes one from the budget, the first
+// attempt of a request is free. Once the budget is spent requests are still sent but no longer
+// retried. Retries also require WithRetry, the budget only limits them.
+func WithRetryBudget(ctx context.Context, n int) context.Context {
+	budget := &atomic.Int64{}
+	budget.Store(int64(n))
+
+	return context.WithValue(ctx, retryBudgetKey{}, budget)
+}
+
+// takeRetry reports whether the budget in ctx, if any, allows one more retry.
+func takeRetry(ctx context.Context) bool {
+	budget, ok := ctx.Value(retryBudgetKey{}).(*atomic.Int64)
+	if !ok {
+		return true
+	}
+
+	return budget.Add(-1) >= 0
+}
+
+func isRetriableStatus(status int) bool {
+	return status == http.StatusTooManyRequests || status >= http.StatusInternalServerError
+}
+
+// retryMiddleware runs each attempt with its own timeout, if one is configured, and retries
+// idempotent requests that failed with a transport error, 429 or 5xx. The delay starts at
+// backoff and doubles after every attempt. The request context bounds all attempts together.
+func (t *tonrocket) retryMiddleware(next RoundTripFunc) RoundTripFunc {
+	return func(req *http.Request) (*http.Response, error) {
+		attempts := 1
+		if t.retry.maxAttempts > 1 && isIdempotent(req) {
+			attempts = t.retry.maxAttempts
+		}
+
+		delay := t.retry.backoff
+		for attempt := 1; ; attempt++ {
+			resp, err := t.attempt(next, req)
+
+			retriable := err != nil || isRetriableStatus(resp.StatusCode)
+
+			// A retry before the maintenance window ends cannot succeed.
+			if resp != nil {
+				if until, ok := maintenanceUntil(resp); ok && until.After(time.Now().Add(delay)) {
+					return resp, nil
+				}
+			}
+
+			if !retriable || attempt >= attempts || req.Context().Err() != nil || !takeRetry(req.Context()) {
+				return resp, err
+			}
+
+			if resp != nil {
+				resp.Body.Close()
+			}
+
+			if m, ok := t.metrics.(RetryMetrics); ok {
+				m.RequestRetried(endpointLabel(req.URL.Path))
+			}
+
+			select {
+			case <-req.Context().Done():
+				return nil, req.Context().Err()
+			case <-time.After(delay):
+			}
+			delay *= 2
+
+			if req.GetBody != nil {
+				body, err := req.GetBody()
+				if err != nil {
+					return nil, err
+				}
+				req = req.Clone(req.Context())
+				req.Body = body
+			}
+		}
+	}
+}
+
+func (t *tonrocket) attempt(next RoundTripFunc, req *http.Request) (*http.Response, error) {
+	if t.perAttemptTimeout <= 0 {
+		return next(req)
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), t.perAttemptTimeout)
+	resp, err := next(req.Clone(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+
+	return resp, nil
+}
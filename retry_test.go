@@ -0,0 +1,39 @@
+package tonrocket
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPerAttemptTimeout(t *testing.T) {
+	var attempts atomic.Int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) == 1 {
+			<-r.Context().Done()
+			return
+		}
+		writeData(w, AppInfo{Name: "app"})
+	}, WithRetry(3, 10*time.Millisecond), WithPerAttemptTimeout(100*time.Millisecond))
+
+	cfg := client.Config()
+	if cfg.RetryMaxAttempts != 3 || cfg.RetryBackoff != 10*time.Millisecond || cfg.PerAttemptTimeout != 100*time.Millisecond {
+		t.Fatalf("config = %+v", cfg)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	info, err := client.AppInfo(ctx)
+	if err != nil {
+		t.Fatalf("AppInfo: %v", err)
+	}
+	if info.Name != "app" {
+		t.Fatalf("name = %q", info.Name)
+	}
+	if got := attempts.Load(); got != 2 {
+		t.Fatalf("attempts = %d, want 2", got)
+	}
+}
@@ -0,0 +1,115 @@
+package tonrocket
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// pageResults is implemented by page[T] so checkSchema can check the first result against T.
+type pageResults interface {
+	resultTarget() any
+}
+
+func (p *page[T]) resultTarget() any {
+	return new(T)
+}
+
+// checkSchema logs top level fields of data that target has no field for, and fields of
+// target that are missing from data. Fields tagged omitempty are not required. For a slice
+// the first item is checked, for a page also its first result, with the field names prefixed
+// by results[0].
+func (t *tonrocket) checkSchema(req *http.Request, data json.RawMessage, target any) {
+	prefix := logPrefix(req.Context()) + req.URL.Path
+
+	t.checkSchemaFields(prefix, "", data, reflect.TypeOf(target))
+
+	if p, ok := target.(pageResults); ok {
+		var results struct {
+			Results []json.RawMessage `json:"results"`
+		}
+		if err := json.Unmarshal(data, &results); err == nil && len(results.Results) > 0 {
+			t.checkSchemaFields(prefix, "results[0].", results.Results[0], reflect.TypeOf(p.resultTarget()))
+		}
+	}
+}
+
+// checkSchemaFields logs the drift of data against typ, each line starting with linePrefix and
+// each field name with fieldPrefix.
+func (t *tonrocket) checkSchemaFields(linePrefix, fieldPrefix string, data json.RawMessage, typ reflect.Type) {
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	if typ.Kind() == reflect.Slice {
+		var items []json.RawMessage
+		if err := json.Unmarshal(data, &items); err != nil || len(items) == 0 {
+			return
+		}
+		data = items[0]
+
+		typ = typ.Elem()
+		for typ.Kind() == reflect.Pointer {
+			typ = typ.Elem()
+		}
+	}
+
+	if typ.Kind() != reflect.Struct {
+		return
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return
+	}
+
+	expected := jsonFields(typ)
+
+	var unexpected, missing []string
+	for name := range fields {
+		if _, ok := expected[name]; !ok {
+			unexpected = append(unexpected, name)
+		}
+	}
+	for name, required := range expected {
+		if _, ok := fields[name]; !ok && required {
+			missing = append(missing, name)
+		}
+	}
+
+	sort.Strings(unexpected)
+	sort.Strings(missing)
+
+	for _, name := range unexpected {
+		t.logger.Printf("%s: unexpected field %q in response", linePrefix, fieldPrefix+name)
+	}
+	for _, name := range missing {
+		t.logger.Printf("%s: expected field %q missing from response", linePrefix, fieldPrefix+name)
+	}
+}
+
+// jsonFields returns the JSON names of the struct fields, mapped to whether they are required.
+func jsonFields(typ reflect.Type) map[string]bool {
+	fields := make(map[string]bool, typ.NumField())
+
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		fields[name] = !strings.Contains(opts, "omitempty")
+	}
+
+	return fields
+}
@@ -0,0 +1,65 @@
+package tonrocket
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type recordingLogger struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+func (l *recordingLogger) Printf(format string, v ...any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.lines = append(l.lines, fmt.Sprintf(format, v...))
+}
+
+func (l *recordingLogger) contains(substr string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, line := range l.lines {
+		if strings.Contains(line, substr) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func TestSchemaWarningsPageResults(t *testing.T) {
+	logger := &recordingLogger{}
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		writeData(w, map[string]any{
+			"total":   1,
+			"limit":   1,
+			"offset":  0,
+			"cursor":  "next",
+			"results": []map[string]any{{"id": 1, "views": 3}},
+		})
+	}, WithSchemaWarnings(true), WithLogger(logger))
+
+	if _, _, err := client.ListInvoices(context.Background(), 1, 0); err != nil {
+		t.Fatalf("ListInvoices: %v", err)
+	}
+
+	for _, want := range []string{
+		`unexpected field "cursor"`,
+		`unexpected field "results[0].views"`,
+		`expected field "results[0].amount" missing`,
+	} {
+		if !logger.contains(want) {
+			t.Errorf("no warning containing %s in %q", want, logger.lines)
+		}
+	}
+	if logger.contains(`"results[0].id"`) {
+		t.Errorf("warned about a known result field: %q", logger.lines)
+	}
+}
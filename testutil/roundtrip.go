@@ -0,0 +1,85 @@
+package testutil
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	tonrocket "github.com/croutondefi/tonrocket-go"
+)
+
+//go:embed testdata/*.json
+var fixtures embed.FS
+
+// RoundTrip marshals v, unmarshals the result into a new value of the same type and marshals
+// that again. It returns the decoded value, or an error if the two encodings differ.
+func RoundTrip(v any) (any, error) {
+	first, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("marshal: %w", err)
+	}
+
+	typ := reflect.TypeOf(v)
+	isPtr := typ.Kind() == reflect.Pointer
+	if isPtr {
+		typ = typ.Elem()
+	}
+
+	decoded := reflect.New(typ)
+	if err := json.Unmarshal(first, decoded.Interface()); err != nil {
+		return nil, fmt.Errorf("unmarshal: %w", err)
+	}
+
+	result := decoded.Interface()
+	if !isPtr {
+		result = decoded.Elem().Interface()
+	}
+
+	second, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("marshal decoded value: %w", err)
+	}
+
+	if !bytes.Equal(first, second) {
+		return nil, fmt.Errorf("round trip is not stable:\n%s\n%s", first, second)
+	}
+
+	return result, nil
+}
+
+// Fixture returns the golden JSON for one of: invoice, transfer, app_info, webhook,
+// withdrawal, multi_cheque.
+func Fixture(name string) []byte {
+	data, err := fixtures.ReadFile("testdata/" + name + ".json")
+	if err != nil {
+		panic(err)
+	}
+
+	return data
+}
+
+// CheckFixtures decodes every golden fixture into its tonrocket type and round trips it.
+func CheckFixtures() error {
+	targets := map[string]any{
+		"invoice":      &tonrocket.Invoice{},
+		"transfer":     &tonrocket.Transfer{},
+		"app_info":     &tonrocket.AppInfo{},
+		"webhook":      &tonrocket.InvoiceWebhookRequest{},
+		"withdrawal":   &tonrocket.Withdrawal{},
+		"multi_cheque": &tonrocket.MultiCheque{},
+	}
+
+	for name, target := range targets {
+		if err := json.Unmarshal(Fixture(name), target); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+
+		if _, err := RoundTrip(target); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+
+	return nil
+}
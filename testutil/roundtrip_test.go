@@ -0,0 +1,40 @@
+package testutil
+
+import "testing"
+
+func TestCheckFixtures(t *testing.T) {
+	if err := CheckFixtures(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSamplesRoundTrip(t *testing.T) {
+	samples := map[string]any{
+		"invoice":                 SampleInvoice(),
+		"transfer":                SampleTransfer(),
+		"app_info":                SampleAppInfo(),
+		"webhook":                 SampleWebhook(),
+		"withdrawal":              SampleWithdrawal(),
+		"multi_cheque":            SampleMultiCheque(),
+		"create invoice request":  SampleCreateInvoiceRequest(),
+		"create transfer request": SampleCreateTransferRequest(),
+	}
+
+	for name, sample := range samples {
+		if _, err := RoundTrip(sample); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+}
+
+func TestSampleRequestsMatchFixtures(t *testing.T) {
+	inv, req := SampleInvoice(), SampleCreateInvoiceRequest()
+	if inv.Description != req.Description || inv.Payload != req.Payload || inv.Currency != req.Currency {
+		t.Errorf("SampleCreateInvoiceRequest does not match SampleInvoice: %+v, %+v", req, inv)
+	}
+
+	transfer, treq := SampleTransfer(), SampleCreateTransferRequest()
+	if transfer.TransferID != treq.TransferID || !transfer.Amount.Equal(treq.Amount) || transfer.TgUserID != treq.TgUserID {
+		t.Errorf("SampleCreateTransferRequest does not match SampleTransfer: %+v, %+v", treq, transfer)
+	}
+}
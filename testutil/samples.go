@@ -0,0 +1,68 @@
+package testutil
+
+import (
+	"encoding/json"
+
+	tonrocket "github.com/croutondefi/tonrocket-go"
+	"github.com/shopspring/decimal"
+)
+
+// The Sample functions return representative values decoded from the golden fixtures, see
+// Fixture. Each call returns a new value that the caller may modify.
+
+func SampleInvoice() *tonrocket.Invoice {
+	return decodeFixture("invoice", &tonrocket.Invoice{})
+}
+
+func SampleTransfer() *tonrocket.Transfer {
+	return decodeFixture("transfer", &tonrocket.Transfer{})
+}
+
+func SampleAppInfo() *tonrocket.AppInfo {
+	return decodeFixture("app_info", &tonrocket.AppInfo{})
+}
+
+func SampleWebhook() *tonrocket.InvoiceWebhookRequest {
+	return decodeFixture("webhook", &tonrocket.InvoiceWebhookRequest{})
+}
+
+func SampleWithdrawal() *tonrocket.Withdrawal {
+	return decodeFixture("withdrawal", &tonrocket.Withdrawal{})
+}
+
+func SampleMultiCheque() *tonrocket.MultiCheque {
+	return decodeFixture("multi_cheque", &tonrocket.MultiCheque{})
+}
+
+// SampleCreateInvoiceRequest returns the request that would create SampleInvoice.
+func SampleCreateInvoiceRequest() tonrocket.CreateInvoiceRequest {
+	return tonrocket.CreateInvoiceRequest{
+		Amount:        12.5,
+		NumPayments:   1,
+		Currency:      tonrocket.TONCurrency,
+		Description:   "Order #42",
+		HiddenMessage: "Thanks!",
+		CallbackURL:   "https://example.com/rocket",
+		Payload:       "order:42",
+		ExpiredIn:     3600,
+	}
+}
+
+// SampleCreateTransferRequest returns the request that would create SampleTransfer.
+func SampleCreateTransferRequest() tonrocket.CreateTransferRequest {
+	return tonrocket.CreateTransferRequest{
+		TransferID:  "payout-42",
+		TgUserID:    87209764,
+		Currency:    tonrocket.TONCurrency,
+		Amount:      decimal.RequireFromString("1.23"),
+		Description: "Payout for order #42",
+	}
+}
+
+func decodeFixture[T any](name string, target *T) *T {
+	if err := json.Unmarshal(Fixture(name), target); err != nil {
+		panic(err)
+	}
+
+	return target
+}
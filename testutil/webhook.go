@@ -0,0 +1,38 @@
+// Package testutil contains helpers for testing code that integrates with tonrocket.
+package testutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	tonrocket "github.com/croutondefi/tonrocket-go"
+)
+
+// WebhookBody returns an invoicePay webhook envelope for inv as Rocket sends it.
+func WebhookBody(inv *tonrocket.Invoice) []byte {
+	body, err := json.Marshal(&tonrocket.InvoiceWebhookRequest{
+		Type:      tonrocket.WebhookTypeInvoicePay,
+		Timestamp: time.Now().UTC(),
+		Data:      inv,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	return body
+}
+
+// SignedWebhookRequest returns a POST request carrying WebhookBody(inv) signed with secret,
+// ready to be passed to an http.Handler.
+func SignedWebhookRequest(secret string, inv *tonrocket.Invoice) *http.Request {
+	body := WebhookBody(inv)
+
+	req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(tonrocket.WebhookSignatureHeader, tonrocket.SignWebhook(secret, body))
+
+	return req
+}
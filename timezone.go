@@ -0,0 +1,27 @@
+package tonrocket
+
+import "time"
+
+// CreatedIn returns Created in loc. The stored value is unchanged.
+func (i *Invoice) CreatedIn(loc *time.Location) time.Time {
+	return i.Created.In(loc)
+}
+
+// PaidIn returns Paid in loc, or the zero time if the invoice is not paid.
+func (i *Invoice) PaidIn(loc *time.Location) time.Time {
+	if i.Paid.IsZero() {
+		return i.Paid
+	}
+
+	return i.Paid.In(loc)
+}
+
+// DisplayTime converts ts to the location set with WithDisplayLocation, UTC by default.
+func (t *tonrocket) DisplayTime(ts time.Time) time.Time {
+	return ts.In(t.displayLocation)
+}
+
+// FormatTime formats ts in the display location with the given layout.
+func (t *tonrocket) FormatTime(ts time.Time, layout string) string {
+	return t.DisplayTime(ts).Format(layout)
+}
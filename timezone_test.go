@@ -0,0 +1,46 @@
+package tonrocket
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+func TestInvoiceTimesAcrossDST(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Berlin moves from CET to CEST at 01:00 UTC on 31 March 2024.
+	inv := &Invoice{
+		Created: time.Date(2024, 3, 31, 0, 30, 0, 0, time.UTC),
+		Paid:    time.Date(2024, 3, 31, 1, 30, 0, 0, time.UTC),
+	}
+
+	const layout = "2006-01-02 15:04 MST"
+	tests := []struct {
+		name string
+		got  time.Time
+		want string
+	}{
+		{"created before the switch", inv.CreatedIn(berlin), "2024-03-31 01:30 CET"},
+		{"paid after the switch", inv.PaidIn(berlin), "2024-03-31 03:30 CEST"},
+		{"display location", NewTonrocket("token", WithDisplayLocation(berlin)).DisplayTime(inv.Paid), "2024-03-31 03:30 CEST"},
+		{"default display location", NewTonrocket("token").DisplayTime(inv.Paid), "2024-03-31 01:30 UTC"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.got.Format(layout); got != tt.want {
+			t.Errorf("%s: %s, want %s", tt.name, got, tt.want)
+		}
+	}
+
+	if !inv.Paid.Equal(inv.PaidIn(berlin)) || inv.Created.Location() != time.UTC {
+		t.Error("the stored times changed")
+	}
+
+	if unpaid := (&Invoice{}).PaidIn(berlin); !unpaid.IsZero() {
+		t.Errorf("PaidIn of an unpaid invoice = %v, want the zero time", unpaid)
+	}
+}
@@ -0,0 +1,180 @@
+package tonrocket
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// TokenProvider issues API tokens. A zero expiry means the token does not expire.
+type TokenProvider interface {
+	Token(ctx context.Context) (token string, expiry time.Time, err error)
+}
+
+const (
+	// TokenRefreshTimeout bounds a single TokenProvider call.
+	TokenRefreshTimeout = 30 * time.Second
+	// TokenRefreshBackoff is how long a failed TokenProvider call is not repeated. Requests in
+	// that time keep using a token that is still valid, or fail with the provider's error.
+	TokenRefreshBackoff = 5 * time.Second
+)
+
+// tokenSource caches the provider's token. Within refreshBefore of the expiry the cached token
+// is still used while a single background refresh runs, once it has expired requests wait for
+// the refresh. Concurrent callers share one provider call.
+type tokenSource struct {
+	provider      TokenProvider
+	refreshBefore time.Duration
+	timeout       time.Duration
+	backoff       time.Duration
+
+	mu          sync.Mutex
+	token       string
+	expiry      time.Time
+	err         error
+	failedUntil time.Time
+	refreshing  chan struct{}
+}
+
+func (s *tokenSource) get(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	backingOff := s.err != nil && time.Now().Before(s.failedUntil)
+
+	if s.token != "" && (s.expiry.IsZero() || time.Now().Before(s.expiry)) {
+		if !s.expiry.IsZero() && time.Until(s.expiry) < s.refreshBefore && s.refreshing == nil && !backingOff {
+			s.refresh()
+		}
+		token := s.token
+		s.mu.Unlock()
+		return token, nil
+	}
+
+	done := s.refreshing
+	if done == nil {
+		if backingOff {
+			err := s.err
+			s.mu.Unlock()
+			return "", fmt.Errorf("unable to refresh token: %w", err)
+		}
+		done = s.refresh()
+	}
+	s.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return "", ctx.Err()
+	case <-done:
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.err != nil {
+		return "", fmt.Errorf("unable to refresh token: %w", s.err)
+	}
+
+	return s.token, nil
+}
+
+// refresh starts a provider call and returns a channel closed when it finishes. s.mu must be
+// held. The call is not bound to any request context, so one cancelled request does not fail
+// the refresh for the others, but it is bounded by s.timeout.
+func (s *tokenSource) refresh() chan struct{} {
+	done := make(chan struct{})
+	s.refreshing = done
+
+	go func() {
+		token, expiry, err := s.call()
+
+		s.mu.Lock()
+		if err == nil {
+			s.token, s.expiry = token, expiry
+		} else {
+			s.failedUntil = time.Now().Add(s.backoff)
+		}
+		s.err = err
+		s.refreshing = nil
+		s.mu.Unlock()
+
+		close(done)
+	}()
+
+	return done
+}
+
+// call runs the provider with s.timeout. A provider that ignores its context is abandoned
+// when the timeout passes.
+func (s *tokenSource) call() (string, time.Time, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+
+	type result struct {
+		token  string
+		expiry time.Time
+		err    error
+	}
+
+	results := make(chan result, 1)
+	go func() {
+		token, expiry, err := s.provider.Token(ctx)
+		results <- result{token, expiry, err}
+	}()
+
+	select {
+	case res := <-results:
+		return res.token, res.expiry, res.err
+	case <-ctx.Done():
+		return "", time.Time{}, fmt.Errorf("token provider did not answer: %w", ctx.Err())
+	}
+}
+
+// invalidate drops the cached token if it is still stale, so the next get refreshes it.
+func (s *tokenSource) invalidate(stale string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token == stale {
+		s.token = ""
+	}
+}
+
+// tokenAuth sets the provider's token on the request. On a 401 the token is refreshed and the
+// request is sent once more, if its body can be replayed.
+func (t *tonrocket) tokenAuth(next RoundTripFunc) RoundTripFunc {
+	return func(req *http.Request) (*http.Response, error) {
+		token, err := t.tokens.get(req.Context())
+		if err != nil {
+			return nil, err
+		}
+
+		req.Header.Set(AuthHeader, token)
+		resp, err := next(req)
+		if err != nil || resp.StatusCode != http.StatusUnauthorized {
+			return resp, err
+		}
+
+		if req.Body != nil && req.GetBody == nil {
+			return resp, nil
+		}
+
+		t.tokens.invalidate(token)
+		token, err = t.tokens.get(req.Context())
+		if err != nil {
+			return resp, nil
+		}
+
+		retry := req.Clone(req.Context())
+		if req.GetBody != nil {
+			if retry.Body, err = req.GetBody(); err != nil {
+				return resp, nil
+			}
+		}
+		resp.Body.Close()
+
+		retry.Header.Set(AuthHeader, token)
+
+		return next(retry)
+	}
+}
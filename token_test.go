@@ -0,0 +1,206 @@
+package tonrocket
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeProvider hands out tok1, tok2, ... valid for validity, or fails with err when set.
+type fakeProvider struct {
+	mu       sync.Mutex
+	calls    int
+	validity time.Duration
+	err      error
+	block    chan struct{}
+}
+
+func (p *fakeProvider) Token(ctx context.Context) (string, time.Time, error) {
+	p.mu.Lock()
+	p.calls++
+	n, err, block := p.calls, p.err, p.block
+	p.mu.Unlock()
+
+	if block != nil {
+		<-block
+	}
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	var expiry time.Time
+	if p.validity > 0 {
+		expiry = time.Now().Add(p.validity)
+	}
+
+	return fmt.Sprintf("tok%d", n), expiry, nil
+}
+
+func (p *fakeProvider) callCount() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.calls
+}
+
+// tokenServer records the token of every request and answers 401 to those in rejected.
+type tokenServer struct {
+	mu       sync.Mutex
+	seen     []string
+	rejected map[string]bool
+}
+
+func (s *tokenServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	token := r.Header.Get(AuthHeader)
+
+	s.mu.Lock()
+	s.seen = append(s.seen, token)
+	rejected := s.rejected[token]
+	s.mu.Unlock()
+
+	if rejected {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	writeData(w, AppInfo{Name: token})
+}
+
+func (s *tokenServer) tokens() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]string(nil), s.seen...)
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met in time")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestTokenBackgroundRefresh(t *testing.T) {
+	provider := &fakeProvider{validity: time.Hour}
+	srv := &tokenServer{}
+	// Every token is within refreshBefore of its expiry, so each use starts a refresh.
+	client := newTestClient(t, srv.ServeHTTP, WithTokenProvider(provider, 2*time.Hour))
+
+	get := func() {
+		t.Helper()
+		if err := client.getRequest(context.Background(), "/app/info", nil, &AppInfo{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	get()
+	get()
+	waitFor(t, func() bool {
+		client.tokens.mu.Lock()
+		defer client.tokens.mu.Unlock()
+		return client.tokens.token == "tok2" && client.tokens.refreshing == nil
+	})
+	get()
+
+	// The second request used the cached token while the refresh ran.
+	if got := fmt.Sprint(srv.tokens()); got != "[tok1 tok1 tok2]" {
+		t.Fatalf("tokens = %v, want [tok1 tok1 tok2]", got)
+	}
+}
+
+func TestTokenRefreshBackoff(t *testing.T) {
+	provider := &fakeProvider{validity: time.Hour}
+	srv := &tokenServer{}
+	client := newTestClient(t, srv.ServeHTTP, WithTokenProvider(provider, 2*time.Hour))
+
+	if _, err := client.tokens.get(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	provider.mu.Lock()
+	provider.err = errors.New("provider down")
+	provider.mu.Unlock()
+
+	for i := 0; i < 20; i++ {
+		if err := client.getRequest(context.Background(), "/app/info", nil, &AppInfo{}); err != nil {
+			t.Fatalf("request %d: %v", i, err)
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	if calls := provider.callCount(); calls != 2 {
+		t.Fatalf("provider called %d times, want 2: the failing refresh is not repeated within the backoff", calls)
+	}
+}
+
+func TestTokenRefreshTimeout(t *testing.T) {
+	provider := &fakeProvider{block: make(chan struct{})}
+	defer close(provider.block)
+
+	client := newTestClient(t, (&tokenServer{}).ServeHTTP, WithTokenProvider(provider, 0))
+	client.tokens.timeout = 50 * time.Millisecond
+
+	start := time.Now()
+	err := client.getRequest(context.Background(), "/app/info", nil, &AppInfo{})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want the refresh to time out", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("request waited %v for a hung provider", elapsed)
+	}
+
+	// The failure is backed off, the next request does not wait for the provider again.
+	if err := client.getRequest(context.Background(), "/app/info", nil, &AppInfo{}); err == nil {
+		t.Fatal("request succeeded without a token")
+	}
+	if calls := provider.callCount(); calls != 1 {
+		t.Fatalf("provider called %d times, want 1", calls)
+	}
+}
+
+func TestTokenUnauthorizedRetry(t *testing.T) {
+	tests := []struct {
+		name     string
+		rejected map[string]bool
+		wantSeen []string
+		wantErr  bool
+	}{
+		{"accepted", nil, []string{"tok1"}, false},
+		{"refreshed after 401", map[string]bool{"tok1": true}, []string{"tok1", "tok2"}, false},
+		{"retried only once", map[string]bool{"tok1": true, "tok2": true}, []string{"tok1", "tok2"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := &tokenServer{rejected: tt.rejected}
+			client := newTestClient(t, srv.ServeHTTP, WithTokenProvider(&fakeProvider{}, 0))
+
+			info := &AppInfo{}
+			err := client.getRequest(context.Background(), "/app/info", nil, info)
+
+			var apiErr *APIError
+			if tt.wantErr != errors.As(err, &apiErr) {
+				t.Fatalf("err = %v, want an error: %v", err, tt.wantErr)
+			}
+			if tt.wantErr && apiErr.StatusCode != http.StatusUnauthorized {
+				t.Fatalf("status = %d, want 401", apiErr.StatusCode)
+			}
+			if !tt.wantErr && info.Name != tt.wantSeen[len(tt.wantSeen)-1] {
+				t.Fatalf("answered with %q", info.Name)
+			}
+
+			got := srv.tokens()
+			if fmt.Sprint(got) != fmt.Sprint(tt.wantSeen) {
+				t.Fatalf("tokens = %v, want %v", got, tt.wantSeen)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package tonrocket
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	tonFlagBounceable    = 0x11
+	tonFlagNonBounceable = 0x51
+	tonFlagTestOnly      = 0x80
+)
+
+// ValidateTONAddress checks addr is either a raw address ("0:<64 hex digits>") or a
+// user-friendly one (48 base64 or base64url characters, e.g. EQ.../UQ...) with valid flags
+// and CRC16 checksum.
+func ValidateTONAddress(addr string) error {
+	if strings.Contains(addr, ":") {
+		return validateRawTONAddress(addr)
+	}
+
+	return validateFriendlyTONAddress(addr)
+}
+
+func validateRawTONAddress(addr string) error {
+	workchain, hash, _ := strings.Cut(addr, ":")
+
+	if _, err := strconv.ParseInt(workchain, 10, 8); err != nil {
+		return fmt.Errorf("%w: invalid workchain %q", ErrInvalidAddress, workchain)
+	}
+
+	if len(hash) != 64 {
+		return fmt.Errorf("%w: raw address hash must be 64 hex digits, got %d", ErrInvalidAddress, len(hash))
+	}
+
+	if _, err := hex.DecodeString(hash); err != nil {
+		return fmt.Errorf("%w: raw address hash is not hex", ErrInvalidAddress)
+	}
+
+	return nil
+}
+
+func validateFriendlyTONAddress(addr string) error {
+	if len(addr) != 48 {
+		return fmt.Errorf("%w: user-friendly address must be 48 characters, got %d", ErrInvalidAddress, len(addr))
+	}
+
+	encoding := base64.StdEncoding
+	if strings.ContainsAny(addr, "-_") {
+		encoding = base64.URLEncoding
+	}
+
+	data, err := encoding.DecodeString(addr)
+	if err != nil {
+		return fmt.Errorf("%w: user-friendly address is not valid base64", ErrInvalidAddress)
+	}
+
+	switch data[0] &^ tonFlagTestOnly {
+	case tonFlagBounceable, tonFlagNonBounceable:
+	default:
+		return fmt.Errorf("%w: unknown address flags 0x%02x", ErrInvalidAddress, data[0])
+	}
+
+	if crc16(data[:34]) != binary.BigEndian.Uint16(data[34:]) {
+		return fmt.Errorf("%w: checksum mismatch", ErrInvalidAddress)
+	}
+
+	return nil
+}
+
+// crc16 is CRC-16/XMODEM, the checksum used by TON user-friendly addresses.
+func crc16(data []byte) uint16 {
+	var crc uint16
+	for _, b := range data {
+		crc ^= uint16(b) << 8
+		for i := 0; i < 8; i++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+
+	return crc
+}
@@ -0,0 +1,98 @@
+package tonrocket
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+
+	"github.com/shopspring/decimal"
+)
+
+type TransferBuilder struct {
+	req       CreateTransferRequest
+	reference string
+}
+
+func NewTransfer(tgUserID int64, amount decimal.Decimal, currency Currency) *TransferBuilder {
+	return &TransferBuilder{
+		req: CreateTransferRequest{
+			TgUserID: tgUserID,
+			Amount:   amount,
+			Currency: currency,
+		},
+	}
+}
+
+func (b *TransferBuilder) WithDescription(description string) *TransferBuilder {
+	b.req.Description = description
+	return b
+}
+
+func (b *TransferBuilder) WithTransferID(transferID string) *TransferBuilder {
+	b.req.TransferID = transferID
+	return b
+}
+
+// WithReference sets the application's own identifier for the payout, such as an order id.
+// It is mixed into the generated transfer id so that separate payouts with identical
+// recipient, amount and description are not deduplicated into one.
+func (b *TransferBuilder) WithReference(reference string) *TransferBuilder {
+	b.reference = reference
+	return b
+}
+
+// Build returns the validated request. Without WithTransferID the transfer id is derived from
+// the recipient, currency, amount, description and reference, so building the same payout
+// again yields the same id and a retried transfer is not paid twice.
+func (b *TransferBuilder) Build() (CreateTransferRequest, error) {
+	req := b.req
+
+	if req.TransferID == "" {
+		req.TransferID = transferID(req, b.reference)
+	}
+
+	return req, req.Validate()
+}
+
+func transferID(req CreateTransferRequest, reference string) string {
+	h := sha256.New()
+	for _, part := range []string{
+		strconv.FormatInt(req.TgUserID, 10),
+		string(req.Currency),
+		req.Amount.String(),
+		req.Description,
+		reference,
+	} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+
+	return hex.EncodeToString(h.Sum(nil)[:16])
+}
+
+// PayoutForInvoice returns the transfer paying amount of inv out to tgUserID. The transfer id
+// is derived from the invoice id, and from the activation number when inv comes from a webhook
+// with a payment, so each paid activation maps to exactly one transfer and a retried payout is
+// not paid twice. The description carries the invoice id and payload for the audit trail.
+func PayoutForInvoice(inv *Invoice, tgUserID int64, amount decimal.Decimal) (CreateTransferRequest, error) {
+	id := inv.ID.String()
+	if id == "" {
+		return CreateTransferRequest{}, errors.New("invoice has no id")
+	}
+
+	transferID := "invoice-" + id
+	if inv.Payment != nil {
+		transferID += "-" + strconv.Itoa(inv.Payment.PaymentNum)
+	}
+
+	description := "Payout for invoice " + id
+	if payload := inv.UserPayload(); payload != "" {
+		description += ": " + payload
+	}
+
+	return NewTransfer(tgUserID, amount, inv.Currency).
+		WithTransferID(transferID).
+		WithDescription(description).
+		Build()
+}
@@ -0,0 +1,104 @@
+package tonrocket
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+type FieldError struct {
+	Field   string
+	Problem string
+}
+
+// ValidationError lists every problem found in a request before it was sent.
+type ValidationError struct {
+	Errors []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	problems := make([]string, len(e.Errors))
+	for i, fe := range e.Errors {
+		problems[i] = fe.Field + ": " + fe.Problem
+	}
+
+	return "invalid request: " + strings.Join(problems, "; ")
+}
+
+func (e *ValidationError) add(field, problem string) {
+	e.Errors = append(e.Errors, FieldError{Field: field, Problem: problem})
+}
+
+func (e *ValidationError) orNil() error {
+	if len(e.Errors) == 0 {
+		return nil
+	}
+
+	return e
+}
+
+func (r CreateTransferRequest) Validate() error {
+	var verr ValidationError
+
+	if r.TgUserID <= 0 {
+		verr.add("tgUserId", "must be a positive Telegram user id")
+	}
+	if !r.Amount.IsPositive() {
+		verr.add("amount", "must be greater than zero")
+	}
+	if r.Currency == "" {
+		verr.add("currency", "is required")
+	}
+	if r.TransferID == "" {
+		verr.add("transferId", "is required, it makes the transfer idempotent")
+	}
+
+	return verr.orNil()
+}
+
+func (r CreateInvoiceRequest) Validate() error {
+	var verr ValidationError
+
+	if r.Amount <= 0 {
+		verr.add("amount", "must be greater than zero")
+	}
+	if r.MinPayment < 0 || r.MinPayment > r.Amount {
+		verr.add("minPayment", "must be between zero and the amount")
+	}
+	if r.NumPayments < 0 {
+		verr.add("numPayments", "must not be negative")
+	}
+	if r.Currency == "" {
+		verr.add("currency", "is required")
+	}
+	if r.ExpiredIn < 0 {
+		verr.add("expiredIn", "must not be negative")
+	}
+	if n := utf8.RuneCountInString(r.Payload); n > MaxPayloadLength {
+		verr.add("payload", fmt.Sprintf("has %d characters, limit is %d", n, MaxPayloadLength))
+	}
+
+	return verr.orNil()
+}
+
+// ErrValidatedLocally is returned instead of a result for a request made with a
+// WithValidateOnly context that passed validation. It means the request was not sent.
+var ErrValidatedLocally = errors.New("request passed client-side validation and was not sent")
+
+type validateOnlyKey struct{}
+
+// WithValidateOnly returns a context that makes CreateInvoice and CreateTransfer check the
+// request without creating anything. Rocket has no server-side validate-only mode, so the
+// check is the client-side Validate plus the currency minimum from CurrencyLimits. A request
+// that passes returns ErrValidatedLocally, one that fails returns the validation error.
+func WithValidateOnly(ctx context.Context) context.Context {
+	return context.WithValue(ctx, validateOnlyKey{}, true)
+}
+
+func isValidateOnly(ctx context.Context) bool {
+	validateOnly, _ := ctx.Value(validateOnlyKey{}).(bool)
+
+	return validateOnly
+}
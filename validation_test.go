@@ -0,0 +1,81 @@
+package tonrocket
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestCreateTransferRequestValidate(t *testing.T) {
+	valid := CreateTransferRequest{
+		TransferID: "payout-1",
+		TgUserID:   42,
+		Currency:   TONCurrency,
+		Amount:     decimal.RequireFromString("1"),
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*CreateTransferRequest)
+		fields []string
+	}{
+		{"valid", func(*CreateTransferRequest) {}, nil},
+		{"zero user id", func(r *CreateTransferRequest) { r.TgUserID = 0 }, []string{"tgUserId"}},
+		{"negative user id", func(r *CreateTransferRequest) { r.TgUserID = -1 }, []string{"tgUserId"}},
+		{"zero amount", func(r *CreateTransferRequest) { r.Amount = decimal.Zero }, []string{"amount"}},
+		{"negative amount", func(r *CreateTransferRequest) { r.Amount = decimal.NewFromInt(-1) }, []string{"amount"}},
+		{"missing currency", func(r *CreateTransferRequest) { r.Currency = "" }, []string{"currency"}},
+		{"missing transfer id", func(r *CreateTransferRequest) { r.TransferID = "" }, []string{"transferId"}},
+		{"every problem", func(r *CreateTransferRequest) { *r = CreateTransferRequest{} },
+			[]string{"tgUserId", "amount", "currency", "transferId"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := valid
+			tt.modify(&req)
+
+			err := req.Validate()
+			if tt.fields == nil {
+				if err != nil {
+					t.Fatalf("Validate() = %v, want nil", err)
+				}
+				return
+			}
+
+			var verr *ValidationError
+			if !errors.As(err, &verr) {
+				t.Fatalf("Validate() = %v, want *ValidationError", err)
+			}
+			var fields []string
+			for _, fe := range verr.Errors {
+				fields = append(fields, fe.Field)
+			}
+			if !reflect.DeepEqual(fields, tt.fields) {
+				t.Fatalf("fields = %v, want %v", fields, tt.fields)
+			}
+		})
+	}
+}
+
+func TestCreateTransferValidatesBeforeSending(t *testing.T) {
+	var sent bool
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		sent = true
+		writeData(w, Transfer{})
+	})
+
+	_, err := client.CreateTransfer(context.Background(), CreateTransferRequest{TransferID: "payout-1"})
+
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("err = %v, want *ValidationError", err)
+	}
+	if sent {
+		t.Fatal("an invalid transfer was sent")
+	}
+}
@@ -0,0 +1,46 @@
+package tonrocket
+
+import (
+	"context"
+	"time"
+)
+
+// MinPollInterval is the shortest interval the polling helpers accept, shorter values are raised to it.
+const MinPollInterval = time.Second
+
+// WaitForPayment polls GetInvoice every interval, varied by the poll jitter, until the invoice
+// is no longer active or ctx is done, and returns the last state seen. Every tick is one API
+// call, so prefer webhooks when tracking many invoices.
+func (t *tonrocket) WaitForPayment(ctx context.Context, id string, interval time.Duration) (*Invoice, error) {
+	if interval < MinPollInterval {
+		interval = MinPollInterval
+	}
+
+	for {
+		inv, err := t.GetInvoice(ctx, id)
+		if err != nil {
+			return inv, err
+		}
+
+		if inv.Status != InvoiceActive {
+			return inv, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return inv, ctx.Err()
+		case <-time.After(t.pollJitter.apply(interval)):
+		}
+	}
+}
+
+// CreateInvoiceAndWait creates the invoice and blocks in WaitForPayment until it is paid,
+// expires or ctx is done. It is meant for scripts and bots, see WaitForPayment for the cost.
+func (t *tonrocket) CreateInvoiceAndWait(ctx context.Context, req CreateInvoiceRequest, interval time.Duration) (*Invoice, error) {
+	inv, err := t.CreateInvoice(ctx, req)
+	if err != nil {
+		return inv, err
+	}
+
+	return t.WaitForPayment(ctx, inv.ID.String(), interval)
+}
@@ -0,0 +1,67 @@
+package tonrocket
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+type WalletType string
+
+const (
+	WalletTelegram  WalletType = "telegram"
+	WalletTonkeeper WalletType = "tonkeeper"
+	WalletTonhub    WalletType = "tonhub"
+)
+
+// WalletLink returns a link for paying the invoice from the given wallet. Rocket invoices
+// are paid inside the Rocket bot and expose no on-chain address, so a ton:// transfer link
+// cannot be built: WalletTelegram gets a tg:// deep link that opens the app directly and
+// the TON wallets get the generic Link. Link is also the string to encode in a QR code.
+func (i *Invoice) WalletLink(wallet WalletType) (string, error) {
+	switch wallet {
+	case WalletTelegram:
+		return telegramDeepLink(i.Link), nil
+	case WalletTonkeeper, WalletTonhub:
+		return i.Link, nil
+	}
+
+	return "", fmt.Errorf("unknown wallet type %q", wallet)
+}
+
+// telegramDeepLink converts https://t.me/<bot>?start=<param> into tg://resolve form and
+// returns link unchanged if it has another shape.
+func telegramDeepLink(link string) string {
+	u, err := url.Parse(link)
+	if err != nil || u.Host != "t.me" {
+		return link
+	}
+
+	bot := strings.Trim(u.Path, "/")
+	if bot == "" || strings.Contains(bot, "/") {
+		return link
+	}
+
+	query := url.Values{"domain": {bot}}
+	if start := u.Query().Get("start"); start != "" {
+		query.Set("start", start)
+	}
+
+	return "tg://resolve?" + query.Encode()
+}
+
+// TelegramLink returns a https://t.me/<bot>?start=<invoice id> deep link into the app's own
+// bot. AppInfo does not report the bot username, so it has to be passed in.
+func (i *Invoice) TelegramLink(botUsername string) (string, error) {
+	bot := strings.TrimPrefix(strings.TrimSpace(botUsername), "@")
+	if bot == "" {
+		return "", errors.New("bot username is required")
+	}
+
+	if i.ID.String() == "" {
+		return "", errors.New("invoice has no id")
+	}
+
+	return "https://t.me/" + url.PathEscape(bot) + "?start=" + url.QueryEscape(i.ID.String()), nil
+}
@@ -0,0 +1,103 @@
+package tonrocket
+
+import (
+	"context"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// WatchBalances polls AppInfo every interval, at least MinPollInterval and varied by the poll
+// jitter, and sends the full balance set whenever any balance changes. The first set is sent
+// right away. The channel is closed when ctx is done. Failed polls are skipped.
+func (t *tonrocket) WatchBalances(ctx context.Context, interval time.Duration) (<-chan []Balance, error) {
+	if interval < MinPollInterval {
+		interval = MinPollInterval
+	}
+
+	info, err := t.AppInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ch := make(chan []Balance, 1)
+	ch <- info.Balances
+
+	go func() {
+		defer close(ch)
+
+		last := info.Balances
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(t.pollJitter.apply(interval)):
+			}
+
+			info, err := t.AppInfo(ctx)
+			if err != nil || balancesEqual(last, info.Balances) {
+				continue
+			}
+			last = info.Balances
+
+			select {
+			case ch <- last:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return ch, nil
+}
+
+func balancesEqual(a, b []Balance) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	amounts := make(map[Currency]Balance, len(a))
+	for _, balance := range a {
+		amounts[balance.Currency] = balance
+	}
+
+	for _, balance := range b {
+		prev, ok := amounts[balance.Currency]
+		if !ok || !prev.Balance.Equal(balance.Balance) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// WaitForBalance polls AppInfo every interval, at least MinPollInterval and varied by the poll
+// jitter, until the balance in currency is at least minimum or ctx is done. Failed polls are
+// retried on the next tick, the last error is returned if ctx ends first.
+func (t *tonrocket) WaitForBalance(ctx context.Context, currency Currency, minimum decimal.Decimal, interval time.Duration) error {
+	if interval < MinPollInterval {
+		interval = MinPollInterval
+	}
+
+	currency = t.normalizeCurrency(currency)
+
+	var lastErr error
+	for {
+		info, err := t.AppInfo(ctx)
+		if err == nil {
+			if balance, _ := info.BalanceOf(currency); balance.GreaterThanOrEqual(minimum) {
+				return nil
+			}
+		}
+		lastErr = err
+
+		select {
+		case <-ctx.Done():
+			if lastErr != nil {
+				return lastErr
+			}
+			return ctx.Err()
+		case <-time.After(t.pollJitter.apply(interval)):
+		}
+	}
+}
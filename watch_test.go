@@ -0,0 +1,100 @@
+package tonrocket
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestWatchBalances(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		polls []time.Time
+	)
+	// Polls return 1, 1, 2, then 2 from then on.
+	amounts := []int64{1, 1, 2}
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		n := len(polls)
+		polls = append(polls, time.Now())
+		mu.Unlock()
+
+		amount := amounts[len(amounts)-1]
+		if n < len(amounts) {
+			amount = amounts[n]
+		}
+		writeData(w, AppInfo{Balances: []Balance{{Currency: TONCurrency, Balance: decimal.NewFromInt(amount)}}})
+	}, WithPollJitter(0, nil))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// A shorter interval than MinPollInterval is raised to it.
+	ch, err := client.WatchBalances(ctx, time.Millisecond)
+	if err != nil {
+		t.Fatalf("WatchBalances: %v", err)
+	}
+
+	for _, want := range []int64{1, 2} {
+		select {
+		case balances := <-ch:
+			if !balances[0].Balance.Equal(decimal.NewFromInt(want)) {
+				t.Fatalf("balance = %s, want %d", balances[0].Balance, want)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("no balances with %d sent", want)
+		}
+	}
+
+	mu.Lock()
+	if len(polls) != 3 {
+		t.Fatalf("%d polls before the change was sent, want 3: the unchanged one is not sent", len(polls))
+	}
+	for i := 1; i < len(polls); i++ {
+		if gap := polls[i].Sub(polls[i-1]); gap < MinPollInterval {
+			t.Fatalf("poll %d came %v after the previous one, want at least %v", i, gap, MinPollInterval)
+		}
+	}
+	mu.Unlock()
+
+	cancel()
+	select {
+	case _, ok := <-ch:
+		if ok {
+			t.Fatal("balances sent after cancellation")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("channel not closed after cancellation")
+	}
+}
+
+func TestBalancesEqual(t *testing.T) {
+	ton := func(amount string) Balance {
+		return Balance{Currency: TONCurrency, Balance: decimal.RequireFromString(amount)}
+	}
+	usdt := Balance{Currency: "USDT", Balance: decimal.NewFromInt(1)}
+
+	tests := []struct {
+		name string
+		a, b []Balance
+		want bool
+	}{
+		{"same", []Balance{ton("1"), usdt}, []Balance{ton("1"), usdt}, true},
+		{"other order", []Balance{ton("1"), usdt}, []Balance{usdt, ton("1")}, true},
+		{"same value, other scale", []Balance{ton("1")}, []Balance{ton("1.000")}, true},
+		{"changed amount", []Balance{ton("1")}, []Balance{ton("2")}, false},
+		{"added currency", []Balance{ton("1")}, []Balance{ton("1"), usdt}, false},
+		{"replaced currency", []Balance{ton("1")}, []Balance{usdt}, false},
+		{"both empty", nil, []Balance{}, true},
+	}
+
+	for _, tt := range tests {
+		if got := balancesEqual(tt.a, tt.b); got != tt.want {
+			t.Errorf("%s: balancesEqual = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
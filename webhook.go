@@ -0,0 +1,61 @@
+package tonrocket
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+const WebhookSignatureHeader = "rocket-pay-signature"
+
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+// SignWebhook returns the signature Rocket puts into the rocket-pay-signature header:
+// hex encoded HMAC-SHA-256 of the body keyed with SHA-256 of the app token.
+func SignWebhook(secret string, body []byte) string {
+	key := sha256.Sum256([]byte(secret))
+	mac := hmac.New(sha256.New, key[:])
+	mac.Write(body)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyWebhook checks signature, the WebhookSignatureHeader value, against body signed with
+// secret, the app token. It returns ErrInvalidSignature if they differ or if secret is empty
+// or only whitespace, since the key derived from a blank secret is public.
+func VerifyWebhook(secret, signature string, body []byte) error {
+	if strings.TrimSpace(secret) == "" {
+		return ErrInvalidSignature
+	}
+
+	expected := SignWebhook(secret, body)
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+// VerifyWebhookMulti succeeds if the signature matches any of the secrets, which allows
+// rotating the secret without rejecting webhooks signed with the old one. Every secret is
+// checked, so neither the result nor the timing tells which one matched. Empty and whitespace
+// secrets, such as an unset environment variable, are skipped as in VerifyWebhook.
+func VerifyWebhookMulti(secrets []string, signature string, body []byte) error {
+	matched := 0
+	for _, secret := range secrets {
+		if strings.TrimSpace(secret) == "" {
+			continue
+		}
+		expected := SignWebhook(secret, body)
+		matched |= subtle.ConstantTimeCompare([]byte(expected), []byte(signature))
+	}
+
+	if matched != 1 {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
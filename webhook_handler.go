@@ -0,0 +1,218 @@
+package tonrocket
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const maxWebhookBodyBytes = 1 << 20
+
+type WebhookOption func(*WebhookHandler)
+
+// WebhookHandler is an http.Handler that verifies and parses Rocket webhooks and delivers
+// them on a channel. The HTTP response is held until the event is acknowledged: Ack answers
+// 200, Nack answers 500 so Rocket delivers the webhook again. Events are therefore
+// processed at least once, and a consumer that crashes before acknowledging gets them
+// redelivered.
+type WebhookHandler struct {
+	secret string
+	events chan *WebhookEvent
+
+	allowed        []*net.IPNet
+	trustedProxies []*net.IPNet
+	skipVerify     bool
+}
+
+// WebhookEvent is a delivered webhook. Only the first Ack or Nack takes effect, later calls
+// are ignored.
+type WebhookEvent struct {
+	Request *InvoiceWebhookRequest
+	done    chan error
+	once    sync.Once
+}
+
+func (e *WebhookEvent) Ack() {
+	e.once.Do(func() { e.done <- nil })
+}
+
+func (e *WebhookEvent) Nack(err error) {
+	if err == nil {
+		err = errors.New("webhook rejected")
+	}
+	e.once.Do(func() { e.done <- err })
+}
+
+// WithInsecureSkipVerify accepts webhooks without checking their signature, for local
+// development only. Anyone who can reach the handler can then forge payments.
+func WithInsecureSkipVerify() WebhookOption {
+	return func(h *WebhookHandler) {
+		h.skipVerify = true
+	}
+}
+
+// NewWebhookHandler returns a handler verifying webhooks with secret, the app token. It panics
+// if secret is empty or only whitespace, unless WithInsecureSkipVerify is set, so a missing
+// secret cannot silently leave the endpoint unauthenticated.
+func NewWebhookHandler(secret string, opts ...WebhookOption) *WebhookHandler {
+	h := &WebhookHandler{
+		secret: secret,
+		events: make(chan *WebhookEvent),
+	}
+
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	if strings.TrimSpace(secret) == "" && !h.skipVerify {
+		panic("tonrocket: webhook secret is empty, set it or use WithInsecureSkipVerify")
+	}
+
+	return h
+}
+
+// Events returns the channel webhooks are delivered on. Every event must be acknowledged
+// with Ack or Nack. Do not read from it when using Batch.
+func (h *WebhookHandler) Events() <-chan *WebhookEvent {
+	return h.events
+}
+
+func (h *WebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	if !h.allowedSource(r) {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookBodyBytes))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if !h.skipVerify {
+		if err := VerifyWebhook(h.secret, r.Header.Get(WebhookSignatureHeader), body); err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+	}
+
+	req, err := ParseWebhookRequest(body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	event := &WebhookEvent{
+		Request: req,
+		done:    make(chan error, 1),
+	}
+
+	select {
+	case h.events <- event:
+	case <-r.Context().Done():
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	select {
+	case err := <-event.done:
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	case <-r.Context().Done():
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+}
+
+type WebhookBatch struct {
+	Requests []*InvoiceWebhookRequest
+	events   []*WebhookEvent
+}
+
+// Ack answers 200 to every webhook in the batch.
+func (b *WebhookBatch) Ack() {
+	for _, e := range b.events {
+		e.Ack()
+	}
+}
+
+// Nack answers 500 to every webhook in the batch so all of them are redelivered.
+func (b *WebhookBatch) Nack(err error) {
+	for _, e := range b.events {
+		e.Nack(err)
+	}
+}
+
+// Batch groups events into batches of up to maxSize, flushing a partial batch maxWait
+// after its first event arrived. None of the webhooks in a batch is answered until the
+// batch is acknowledged, so maxWait also adds to Rocket's view of the response time and
+// should stay well below its delivery timeout. Batch must be called at most once.
+//
+// Batching stops when ctx is done: the events of a batch not yet delivered are nacked, so
+// Rocket redelivers them, and the returned channel is closed.
+func (h *WebhookHandler) Batch(ctx context.Context, maxSize int, maxWait time.Duration) <-chan *WebhookBatch {
+	if maxSize < 1 {
+		maxSize = 1
+	}
+
+	batches := make(chan *WebhookBatch)
+
+	go func() {
+		defer close(batches)
+
+		var (
+			batch *WebhookBatch
+			timer <-chan time.Time
+		)
+
+		flush := func() bool {
+			select {
+			case batches <- batch:
+				batch, timer = nil, nil
+				return true
+			case <-ctx.Done():
+				batch.Nack(ctx.Err())
+				return false
+			}
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				if batch != nil {
+					batch.Nack(ctx.Err())
+				}
+				return
+			case e := <-h.events:
+				if batch == nil {
+					batch = &WebhookBatch{}
+					timer = time.After(maxWait)
+				}
+				batch.Requests = append(batch.Requests, e.Request)
+				batch.events = append(batch.events, e)
+
+				if len(batch.events) >= maxSize && !flush() {
+					return
+				}
+			case <-timer:
+				if !flush() {
+					return
+				}
+			}
+		}
+	}()
+
+	return batches
+}
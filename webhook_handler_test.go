@@ -0,0 +1,96 @@
+package tonrocket
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func serveWebhook(h *WebhookHandler) <-chan int {
+	body := []byte(`{"type":"invoicePay","timestamp":"2024-01-01T00:00:00Z","data":{"id":1}}`)
+
+	status := make(chan int, 1)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body)).WithContext(ctx))
+		status <- rec.Code
+	}()
+
+	return status
+}
+
+func TestWebhookAckOnce(t *testing.T) {
+	h := NewWebhookHandler("", WithInsecureSkipVerify())
+	status := serveWebhook(h)
+
+	var event *WebhookEvent
+	select {
+	case event = <-h.Events():
+	case got := <-status:
+		t.Fatalf("handler answered %d before delivering the event", got)
+	}
+	event.Ack()
+	event.Ack()
+	event.Nack(errors.New("too late"))
+
+	if got := <-status; got != http.StatusOK {
+		t.Fatalf("status = %d, want %d", got, http.StatusOK)
+	}
+}
+
+func TestWebhookBatchStopsOnContext(t *testing.T) {
+	h := NewWebhookHandler("", WithInsecureSkipVerify())
+	ctx, cancel := context.WithCancel(context.Background())
+	batches := h.Batch(ctx, 10, time.Hour)
+
+	status := serveWebhook(h)
+	// Let the batcher take the event into its pending batch.
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+
+	if got := <-status; got != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want %d for a nacked pending batch", got, http.StatusInternalServerError)
+	}
+
+	select {
+	case _, ok := <-batches:
+		if ok {
+			t.Fatal("received a batch after cancellation")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("batch channel was not closed")
+	}
+}
+
+func TestNewWebhookHandlerSecret(t *testing.T) {
+	tests := []struct {
+		name      string
+		secret    string
+		opts      []WebhookOption
+		wantPanic bool
+	}{
+		{"empty", "", nil, true},
+		{"whitespace", " \t\n", nil, true},
+		{"set", "token", nil, false},
+		{"empty with skip verify", "", []WebhookOption{WithInsecureSkipVerify()}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if got := recover() != nil; got != tt.wantPanic {
+					t.Fatalf("panicked = %v, want %v", got, tt.wantPanic)
+				}
+			}()
+
+			NewWebhookHandler(tt.secret, tt.opts...)
+		})
+	}
+}
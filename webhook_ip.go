@@ -0,0 +1,98 @@
+package tonrocket
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// WithWebhookAllowedCIDRs only accepts webhooks from addresses in cidrs and answers 403 to
+// the rest. This complements signature verification, which still runs for allowed addresses,
+// it does not replace it. Behind a load balancer also set WithWebhookTrustedProxies, otherwise
+// the balancer's address is checked. It panics if a CIDR does not parse.
+func WithWebhookAllowedCIDRs(cidrs []string) WebhookOption {
+	nets := mustParseCIDRs(cidrs)
+
+	return func(h *WebhookHandler) {
+		h.allowed = nets
+	}
+}
+
+// WithWebhookTrustedProxies sets the proxies whose X-Forwarded-For header is believed. The
+// client address is the rightmost X-Forwarded-For entry that is not a trusted proxy, a header
+// from any other peer is ignored. It panics if a CIDR does not parse.
+func WithWebhookTrustedProxies(cidrs []string) WebhookOption {
+	nets := mustParseCIDRs(cidrs)
+
+	return func(h *WebhookHandler) {
+		h.trustedProxies = nets
+	}
+}
+
+func mustParseCIDRs(cidrs []string) []*net.IPNet {
+	nets := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, n, err := net.ParseCIDR(strings.TrimSpace(cidr))
+		if err != nil {
+			panic(fmt.Sprintf("tonrocket: invalid CIDR %q: %v", cidr, err))
+		}
+		nets = append(nets, n)
+	}
+
+	return nets
+}
+
+func containsIP(nets []*net.IPNet, ip net.IP) bool {
+	for _, n := range nets {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// clientIP returns the address the request came from, following X-Forwarded-For through
+// trusted proxies. A request from a trusted proxy without X-Forwarded-For was made by the
+// proxy itself, its own address is returned. A malformed entry returns nil.
+func (h *WebhookHandler) clientIP(r *http.Request) net.IP {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil || !containsIP(h.trustedProxies, ip) {
+		return ip
+	}
+
+	forwarded := r.Header.Values("X-Forwarded-For")
+	if len(forwarded) == 0 {
+		return ip
+	}
+
+	hops := strings.Split(strings.Join(forwarded, ","), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := net.ParseIP(strings.TrimSpace(hops[i]))
+		if hop == nil {
+			return nil
+		}
+		if !containsIP(h.trustedProxies, hop) {
+			return hop
+		}
+		ip = hop
+	}
+
+	return ip
+}
+
+func (h *WebhookHandler) allowedSource(r *http.Request) bool {
+	if len(h.allowed) == 0 {
+		return true
+	}
+
+	ip := h.clientIP(r)
+
+	return ip != nil && containsIP(h.allowed, ip)
+}
@@ -0,0 +1,75 @@
+package tonrocket
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWebhookClientIP(t *testing.T) {
+	h := NewWebhookHandler("token", WithWebhookTrustedProxies([]string{"10.0.0.0/8", "fd00::/8"}))
+
+	tests := []struct {
+		name      string
+		remote    string
+		forwarded []string
+		want      string
+	}{
+		{"direct peer", "203.0.113.7:443", nil, "203.0.113.7"},
+		{"untrusted peer sending X-Forwarded-For", "203.0.113.7:443", []string{"198.51.100.1"}, "203.0.113.7"},
+		{"behind a trusted proxy", "10.0.0.2:443", []string{"198.51.100.1"}, "198.51.100.1"},
+		{"spoofed leftmost hop", "10.0.0.2:443", []string{"192.0.2.66, 198.51.100.1"}, "198.51.100.1"},
+		{"chain of trusted proxies", "10.0.0.2:443", []string{"198.51.100.1, 10.0.0.3"}, "198.51.100.1"},
+		{"several headers", "10.0.0.2:443", []string{"192.0.2.66", "198.51.100.1, 10.0.0.3"}, "198.51.100.1"},
+		{"IPv6 client", "[fd00::2]:443", []string{"2001:db8::1"}, "2001:db8::1"},
+		{"IPv6 untrusted peer", "[2001:db8::9]:443", []string{"2001:db8::1"}, "2001:db8::9"},
+		{"trusted proxy without X-Forwarded-For", "10.0.0.2:443", nil, "10.0.0.2"},
+		{"only trusted hops", "10.0.0.2:443", []string{"10.0.0.4, 10.0.0.3"}, "10.0.0.4"},
+		{"malformed hop", "10.0.0.2:443", []string{"198.51.100.1, garbage"}, "<nil>"},
+		{"empty hop", "10.0.0.2:443", []string{""}, "<nil>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("POST", "/", nil)
+			r.RemoteAddr = tt.remote
+			for _, value := range tt.forwarded {
+				r.Header.Add("X-Forwarded-For", value)
+			}
+
+			if got := h.clientIP(r).String(); got != tt.want {
+				t.Fatalf("clientIP = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWebhookAllowedSource(t *testing.T) {
+	h := NewWebhookHandler("token",
+		WithWebhookAllowedCIDRs([]string{"198.51.100.0/24"}),
+		WithWebhookTrustedProxies([]string{"10.0.0.0/8"}),
+	)
+
+	tests := []struct {
+		name      string
+		remote    string
+		forwarded string
+		want      bool
+	}{
+		{"allowed client through proxy", "10.0.0.2:443", "198.51.100.1", true},
+		{"spoofed allowed address", "10.0.0.2:443", "198.51.100.1, 192.0.2.66", false},
+		{"allowed address from untrusted peer", "192.0.2.66:443", "198.51.100.1", false},
+		{"proxy itself", "10.0.0.2:443", "", false},
+	}
+
+	for _, tt := range tests {
+		r := httptest.NewRequest("POST", "/", nil)
+		r.RemoteAddr = tt.remote
+		if tt.forwarded != "" {
+			r.Header.Set("X-Forwarded-For", tt.forwarded)
+		}
+
+		if got := h.allowedSource(r); got != tt.want {
+			t.Errorf("%s: allowed = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package tonrocket
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestVerifyWebhookMulti(t *testing.T) {
+	body := []byte(`{"type":"invoicePay"}`)
+
+	tests := []struct {
+		name     string
+		secrets  []string
+		signedBy string
+		wantErr  bool
+	}{
+		{"current secret", []string{"new", "old"}, "new", false},
+		{"old secret during rotation", []string{"new", "old"}, "old", false},
+		{"unknown secret", []string{"new", "old"}, "other", true},
+		{"unset old secret", []string{"new", ""}, "", true},
+		{"whitespace secret", []string{"new", "  "}, "  ", true},
+		{"no secrets", nil, "new", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifyWebhookMulti(tt.secrets, SignWebhook(tt.signedBy, body), body)
+			if tt.wantErr != errors.Is(err, ErrInvalidSignature) || (!tt.wantErr && err != nil) {
+				t.Fatalf("err = %v, want error: %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestVerifyWebhook(t *testing.T) {
+	body := []byte(`{"type":"invoicePay"}`)
+
+	if err := VerifyWebhook("token", SignWebhook("token", body), body); err != nil {
+		t.Fatalf("valid signature: %v", err)
+	}
+	if err := VerifyWebhook("token", SignWebhook("token", body), []byte("{}")); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("tampered body: %v", err)
+	}
+	if err := VerifyWebhook("", SignWebhook("", body), body); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("empty secret: %v", err)
+	}
+}
@@ -0,0 +1,173 @@
+package tonrocket
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+type CreateWithdrawalRequest struct {
+	Network      Network         `json:"network"`
+	Address      string          `json:"address"`
+	Currency     Currency        `json:"currency"`
+	Amount       decimal.Decimal `json:"amount"`
+	WithdrawalID string          `json:"withdrawalId"`
+	Comment      string          `json:"comment"`
+}
+
+type Withdrawal struct {
+	Network      Network         `json:"network"`
+	Address      string          `json:"address"`
+	Currency     Currency        `json:"currency"`
+	Amount       decimal.Decimal `json:"amount"`
+	WithdrawalID string          `json:"withdrawalId"`
+	Status       string          `json:"status"`
+	Comment      string          `json:"comment"`
+	TxHash       string          `json:"txHash"`
+	TxLink       string          `json:"txLink"`
+}
+
+type WithdrawalFees struct {
+	Currency    Currency        `json:"code"`
+	MinWithdraw decimal.Decimal `json:"minWithdraw"`
+	Fees        []*NetworkFee   `json:"fees"`
+}
+
+type NetworkFee struct {
+	NetworkCode Network `json:"networkCode"`
+	FeeWithdraw struct {
+		Currency Currency        `json:"currency"`
+		Fee      decimal.Decimal `json:"fee"`
+	} `json:"feeWithdraw"`
+}
+
+type InsufficientBalanceError struct {
+	Currency  Currency
+	Required  decimal.Decimal
+	Available decimal.Decimal
+}
+
+func (e *InsufficientBalanceError) Shortfall() decimal.Decimal {
+	return e.Required.Sub(e.Available)
+}
+
+func (e *InsufficientBalanceError) Error() string {
+	return fmt.Sprintf("insufficient %s balance: required %s, available %s, short by %s",
+		e.Currency, e.Required, e.Available, e.Shortfall())
+}
+
+// CreateWithdrawal sends funds from the app balance to an external address. The Rocket API
+// has no endpoint to cancel a withdrawal once it is created, so any hold or review has to
+// happen before this call.
+func (t *tonrocket) CreateWithdrawal(ctx context.Context, req CreateWithdrawalRequest) (*Withdrawal, error) {
+	req.Currency = t.normalizeCurrency(req.Currency)
+
+	if req.Network == "" {
+		return nil, errors.New("network is required")
+	}
+
+	if t.addressValidation {
+		if err := ValidateAddress(req.Network, req.Address); err != nil {
+			return nil, err
+		}
+	}
+
+	if t.withdrawalMinimums {
+		if err := t.CheckMinimum(OperationWithdraw, req.Currency, req.Amount); err != nil {
+			return nil, err
+		}
+	}
+
+	if t.withdrawalMinimums || t.balanceCheck {
+		fees, err := t.WithdrawalFees(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("unable to fetch withdrawal fees: %w", err)
+		}
+		fee := findNetworkFee(fees, req.Currency, req.Network)
+
+		if t.withdrawalMinimums {
+			if err := checkWithdrawalFee(req, fee); err != nil {
+				return nil, err
+			}
+		}
+
+		if t.balanceCheck {
+			if err := t.checkWithdrawalBalance(ctx, req, fee); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	var resp = &Withdrawal{}
+
+	err := t.postRequest(markIdempotent(ctx), t.endpoints.Withdrawal, req, resp)
+
+	return resp, err
+}
+
+func (t *tonrocket) WithdrawalFees(ctx context.Context) ([]*WithdrawalFees, error) {
+	var resp []*WithdrawalFees
+	err := t.getRequest(ctx, t.endpoints.WithdrawalFees, nil, &resp)
+
+	return resp, err
+}
+
+// checkWithdrawalBalance checks the app balance covers the amount plus the network fee, if
+// any. The amount currency is always checked first, then the fee currency when it differs.
+func (t *tonrocket) checkWithdrawalBalance(ctx context.Context, req CreateWithdrawalRequest, fee *NetworkFee) error {
+	required := []Balance{{Currency: req.Currency, Balance: req.Amount}}
+
+	if fee != nil {
+		if fee.FeeWithdraw.Currency == req.Currency {
+			required[0].Balance = required[0].Balance.Add(fee.FeeWithdraw.Fee)
+		} else {
+			required = append(required, Balance{Currency: fee.FeeWithdraw.Currency, Balance: fee.FeeWithdraw.Fee})
+		}
+	}
+
+	info, err := t.AppInfo(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to fetch app balance: %w", err)
+	}
+
+	for _, r := range required {
+		available, _ := info.BalanceOf(r.Currency)
+		if available.LessThan(r.Balance) {
+			return &InsufficientBalanceError{
+				Currency:  r.Currency,
+				Required:  r.Balance,
+				Available: available,
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkWithdrawalFee rejects amounts that the network fee, when charged in the same currency,
+// would consume entirely.
+func checkWithdrawalFee(req CreateWithdrawalRequest, fee *NetworkFee) error {
+	if fee != nil && fee.FeeWithdraw.Currency == req.Currency && !req.Amount.GreaterThan(fee.FeeWithdraw.Fee) {
+		return fmt.Errorf("withdraw amount %s does not cover the %s network fee of %s %s",
+			req.Amount, req.Network, fee.FeeWithdraw.Fee, req.Currency)
+	}
+
+	return nil
+}
+
+func findNetworkFee(fees []*WithdrawalFees, currency Currency, network Network) *NetworkFee {
+	for _, f := range fees {
+		if f.Currency != currency {
+			continue
+		}
+		for _, n := range f.Fees {
+			if n.NetworkCode == network {
+				return n
+			}
+		}
+	}
+
+	return nil
+}
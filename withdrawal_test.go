@@ -0,0 +1,185 @@
+package tonrocket
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+// withdrawalServer serves a TON network fee in TONCOIN, a TONCOIN balance and accepts
+// withdrawals, counting them in sent.
+func withdrawalServer(balance, fee string, sent *int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case DefaultEndpoints.WithdrawalFees:
+			writeData(w, []map[string]any{{
+				"code":        TONCurrency,
+				"minWithdraw": "0.1",
+				"fees": []map[string]any{{
+					"networkCode": NetworkTON,
+					"feeWithdraw": map[string]any{"currency": TONCurrency, "fee": fee},
+				}},
+			}})
+		case DefaultEndpoints.AppInfo:
+			writeData(w, AppInfo{Balances: []Balance{{Currency: TONCurrency, Balance: decimal.RequireFromString(balance)}}})
+		case DefaultEndpoints.Withdrawal:
+			*sent++
+			writeData(w, Withdrawal{Status: "pending"})
+		default:
+			writeError(w, http.StatusNotFound, "not found")
+		}
+	}
+}
+
+func TestWithdrawalBalanceCheck(t *testing.T) {
+	tests := []struct {
+		name          string
+		balance       string
+		wantShortfall string
+	}{
+		{"covers amount and fee", "10.05", ""},
+		{"just below amount and fee", "10.049999999", "0.000000001"},
+		{"covers amount only", "10", "0.05"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sent int
+			client := newTestClient(t, withdrawalServer(tt.balance, "0.05", &sent), WithBalanceCheck(), WithoutAddressValidation())
+
+			_, err := client.CreateWithdrawal(context.Background(), CreateWithdrawalRequest{
+				Network:  NetworkTON,
+				Address:  "address",
+				Currency: TONCurrency,
+				Amount:   decimal.RequireFromString("10"),
+			})
+
+			if tt.wantShortfall == "" {
+				if err != nil || sent != 1 {
+					t.Fatalf("err = %v, sent = %d, want the withdrawal sent", err, sent)
+				}
+				return
+			}
+
+			var balanceErr *InsufficientBalanceError
+			if !errors.As(err, &balanceErr) {
+				t.Fatalf("err = %v, want *InsufficientBalanceError", err)
+			}
+			if !balanceErr.Shortfall().Equal(decimal.RequireFromString(tt.wantShortfall)) {
+				t.Fatalf("shortfall = %s, want %s", balanceErr.Shortfall(), tt.wantShortfall)
+			}
+			if sent != 0 {
+				t.Fatal("the withdrawal was sent")
+			}
+		})
+	}
+}
+
+func TestWithdrawalMinimums(t *testing.T) {
+	// The embedded currency limits put the TONCOIN withdrawal minimum at 0.1.
+	tests := []struct {
+		name    string
+		amount  string
+		fee     string
+		wantErr string
+	}{
+		{"above minimum and fee", "0.5", "0.05", ""},
+		{"above fee, below minimum", "0.08", "0.05", "below the minimum"},
+		{"above minimum, consumed by fee", "0.15", "0.2", "does not cover"},
+		{"equal to fee", "0.2", "0.2", "does not cover"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sent int
+			client := newTestClient(t, withdrawalServer("100", tt.fee, &sent), WithWithdrawalMinimums(), WithoutAddressValidation())
+
+			_, err := client.CreateWithdrawal(context.Background(), CreateWithdrawalRequest{
+				Network:  NetworkTON,
+				Address:  "address",
+				Currency: TONCurrency,
+				Amount:   decimal.RequireFromString(tt.amount),
+			})
+
+			if tt.wantErr == "" {
+				if err != nil || sent != 1 {
+					t.Fatalf("err = %v, sent = %d, want the withdrawal sent", err, sent)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("err = %v, want it to mention %q", err, tt.wantErr)
+			}
+			if sent != 0 {
+				t.Fatal("the withdrawal was sent")
+			}
+		})
+	}
+}
+
+func TestWithdrawalBalanceCheckOrder(t *testing.T) {
+	const usdt Currency = "USDT"
+
+	// Both the USDT amount and the TONCOIN fee are short, the amount currency must be reported.
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case DefaultEndpoints.WithdrawalFees:
+			writeData(w, []map[string]any{{
+				"code": usdt,
+				"fees": []map[string]any{{
+					"networkCode": NetworkTON,
+					"feeWithdraw": map[string]any{"currency": TONCurrency, "fee": "0.05"},
+				}},
+			}})
+		case DefaultEndpoints.AppInfo:
+			writeData(w, AppInfo{Balances: []Balance{{Currency: usdt, Balance: decimal.RequireFromString("5")}}})
+		default:
+			writeError(w, http.StatusNotFound, "not found")
+		}
+	}, WithBalanceCheck(), WithoutAddressValidation())
+
+	for i := 0; i < 20; i++ {
+		_, err := client.CreateWithdrawal(context.Background(), CreateWithdrawalRequest{
+			Network:  NetworkTON,
+			Address:  "address",
+			Currency: usdt,
+			Amount:   decimal.RequireFromString("10"),
+		})
+
+		var balanceErr *InsufficientBalanceError
+		if !errors.As(err, &balanceErr) {
+			t.Fatalf("err = %v, want *InsufficientBalanceError", err)
+		}
+		if balanceErr.Currency != usdt {
+			t.Fatalf("currency = %s, want %s", balanceErr.Currency, usdt)
+		}
+	}
+}
+
+func TestWithdrawalChecksFetchFeesOnce(t *testing.T) {
+	var sent, feeCalls int
+	server := withdrawalServer("100", "0.05", &sent)
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == DefaultEndpoints.WithdrawalFees {
+			feeCalls++
+		}
+		server(w, r)
+	}, WithBalanceCheck(), WithWithdrawalMinimums(), WithoutAddressValidation())
+
+	_, err := client.CreateWithdrawal(context.Background(), CreateWithdrawalRequest{
+		Network:  NetworkTON,
+		Address:  "address",
+		Currency: TONCurrency,
+		Amount:   decimal.RequireFromString("1"),
+	})
+	if err != nil || sent != 1 {
+		t.Fatalf("err = %v, sent = %d, want the withdrawal sent", err, sent)
+	}
+	if feeCalls != 1 {
+		t.Fatalf("fee calls = %d, want 1", feeCalls)
+	}
+}